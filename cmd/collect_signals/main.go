@@ -34,6 +34,7 @@ import (
 	"github.com/ossf/criticality_score/v2/cmd/collect_signals/vcs"
 	"github.com/ossf/criticality_score/v2/internal/collector"
 	log "github.com/ossf/criticality_score/v2/internal/log"
+	"github.com/ossf/criticality_score/v2/internal/signalio"
 )
 
 const (
@@ -50,6 +51,41 @@ const (
 	configScoringColumnName = "scoring-column-name"
 )
 
+// sourceConfigs lists the config entries used to enable or disable each
+// source, and whether the source is enabled if its entry is not set.
+//
+// Only the sources collected by the original worker are enabled by default, so
+// that adding a source does not silently change the cost of a run.
+var sourceConfigs = []struct {
+	key     string
+	source  collector.SourceType
+	enabled bool
+}{
+	{key: "source-github-repo", source: collector.SourceTypeGithubRepo, enabled: true},
+	{key: "source-github-issues", source: collector.SourceTypeGithubIssues, enabled: true},
+	{key: "source-github-mentions", source: collector.SourceTypeGitHubMentions, enabled: true},
+	{key: "source-depsdev", source: collector.SourceTypeDepsDev, enabled: true},
+	{key: "source-github-issue-triage", source: collector.SourceTypeGitHubIssueTriage},
+	{key: "source-github-contributors", source: collector.SourceTypeGitHubContributors},
+	{key: "source-github-pulls", source: collector.SourceTypeGitHubPulls},
+	{key: "source-github-hygiene", source: collector.SourceTypeGitHubHygiene},
+	{key: "source-github-commit-activity", source: collector.SourceTypeGitHubCommitActivity},
+	{key: "source-github-languages", source: collector.SourceTypeGitHubLanguages},
+	{key: "source-github-releases", source: collector.SourceTypeGitHubReleases},
+	{key: "source-github-advisories", source: collector.SourceTypeGitHubAdvisories},
+	{key: "source-github-dependents", source: collector.SourceTypeGitHubDependents},
+	{key: "source-gitlab-repo", source: collector.SourceTypeGitLabRepo},
+	{key: "source-gitlab-issues", source: collector.SourceTypeGitLabIssues},
+	{key: "source-gitea-repo", source: collector.SourceTypeGiteaRepo},
+	{key: "source-gitea-issues", source: collector.SourceTypeGiteaIssues},
+	{key: "source-git-clone", source: collector.SourceTypeGitClone},
+	{key: "source-osv", source: collector.SourceTypeOSV},
+	{key: "source-scorecard", source: collector.SourceTypeScorecard},
+	{key: "source-downloads", source: collector.SourceTypeDownloads},
+}
+
+var printSchemaFlag = flag.String("print-schema", "", "print a schema of `type` json-schema, bigquery-json or bigquery-csv for the output and exit.")
+
 type runner interface {
 	Run() error
 }
@@ -190,13 +226,6 @@ func main() {
 		csvBucketURL = ""
 	}
 
-	// The GitHub authentication server may be unavailable if it is starting
-	// at the same time. Wait until it can be reached.
-	waitForRPCServer(logger, os.Getenv("GITHUB_AUTH_SERVER"), githubAuthServerMaxAttemps)
-
-	// Bump the # idle conns per host
-	http.DefaultTransport.(*http.Transport).MaxIdleConnsPerHost = 5
-
 	// Preapre the options for the collector.
 	opts := []collector.Option{
 		collector.EnableAllSources(),
@@ -205,6 +234,38 @@ func main() {
 		collector.GCPDatasetTTL(gcpDatasetTTL),
 	}
 
+	// Enable or disable each of the sources.
+	for _, sc := range sourceConfigs {
+		enabled, err := parseBool(criticalityConfig[sc.key], sc.enabled)
+		if err != nil {
+			logger.With(zap.Error(err)).Fatal(fmt.Sprintf("Failed parsing %q setting", sc.key))
+		}
+		if enabled {
+			opts = append(opts, collector.EnableSource(sc.source))
+		} else {
+			opts = append(opts, collector.DisableSource(sc.source))
+		}
+	}
+
+	// Print the schema instead of starting the worker, if requested.
+	if *printSchemaFlag != "" {
+		var t signalio.SchemaType
+		if err := t.UnmarshalText([]byte(*printSchemaFlag)); err != nil {
+			logger.With(zap.Error(err)).Fatal("Failed parsing schema type")
+		}
+		if err := printSchema(context.Background(), os.Stdout, logger, t, scoringEnabled, scoringConfigFile, scoringColumnName, opts); err != nil {
+			logger.With(zap.Error(err)).Fatal("Failed to print schema")
+		}
+		return
+	}
+
+	// The GitHub authentication server may be unavailable if it is starting
+	// at the same time. Wait until it can be reached.
+	waitForRPCServer(logger, os.Getenv("GITHUB_AUTH_SERVER"), githubAuthServerMaxAttemps)
+
+	// Bump the # idle conns per host
+	http.DefaultTransport.(*http.Transport).MaxIdleConnsPerHost = 5
+
 	w, err := NewWorker(context.Background(), logger, scoringEnabled, scoringConfigFile, scoringColumnName, csvBucketURL, opts)
 	if err != nil {
 		// Fatal exits.
@@ -5,8 +5,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/url"
 	"os"
+	"time"
 
 	githubstats "github.com/ossf/scorecard/v4/clients/githubrepo/stats"
 	"github.com/ossf/scorecard/v4/cron/data"
@@ -23,8 +25,9 @@ import (
 )
 
 const (
-	collectionDateColumnName = "collection_date"
-	commitIDColumnName       = "worker_commit_id"
+	collectionDateColumnName   = "collection_date"
+	commitIDColumnName         = "worker_commit_id"
+	collectionErrorsColumnName = "collection_errors"
 )
 
 type collectWorker struct {
@@ -52,12 +55,8 @@ func (w *collectWorker) Process(ctx context.Context, req *data.ScorecardBatchReq
 
 	// Prepare the output writer
 	extras := []string{}
-	if w.s != nil {
-		extras = append(extras, w.scoreColumnName)
-	}
-	extras = append(extras, collectionDateColumnName)
-	if commitID := vcs.CommitID(); commitID != vcs.MissingCommitID {
-		extras = append(extras, commitIDColumnName)
+	for _, f := range extraColumns(w.s != nil, w.scoreColumnName) {
+		extras = append(extras, f.Key)
 	}
 
 	var jsonOutput bytes.Buffer
@@ -86,7 +85,12 @@ func (w *collectWorker) Process(ctx context.Context, req *data.ScorecardBatchReq
 			continue
 		}
 		ss, err := w.c.Collect(ctx, u, jobID)
-		if err != nil {
+		var collectErrs collector.CollectionErrors
+		if errors.As(err, &collectErrs) {
+			// The signals from the sources that succeeded are still written,
+			// with the failures recorded alongside them.
+			repoLogger.With(zap.Error(err)).Warn("Failed to collect some signals")
+		} else if err != nil {
 			if errors.Is(err, collector.ErrUncollectableRepo) {
 				repoLogger.With(zap.Error(err)).Warn("Repo is uncollectable")
 				continue
@@ -119,6 +123,13 @@ func (w *collectWorker) Process(ctx context.Context, req *data.ScorecardBatchReq
 			})
 		}
 
+		// Record which sources failed, if any.
+		errorsField := signalio.Field{Key: collectionErrorsColumnName}
+		if collectErrs != nil {
+			errorsField.Value = collectErrs.Error()
+		}
+		extras = append(extras, errorsField)
+
 		// Write the signals to storage.
 		if err := jsonOut.WriteSignals(ss, extras...); err != nil {
 			return fmt.Errorf("failed writing signals: %w", err)
@@ -156,6 +167,42 @@ func (w *collectWorker) PostProcess() {
 	w.exporter.Flush()
 }
 
+// extraColumns returns the columns added to each record after the signals, in
+// order. The Value of each is a zero value of the type written to the column.
+func extraColumns(scoring bool, scoreColumnName string) []signalio.Field {
+	var extras []signalio.Field
+	if scoring {
+		// The score is written as a formatted string.
+		extras = append(extras, signalio.Field{Key: scoreColumnName, Value: ""})
+	}
+	extras = append(extras, signalio.Field{Key: collectionDateColumnName, Value: time.Time{}})
+	if commitID := vcs.CommitID(); commitID != vcs.MissingCommitID {
+		extras = append(extras, signalio.Field{Key: commitIDColumnName, Value: ""})
+	}
+	extras = append(extras, signalio.Field{Key: collectionErrorsColumnName, Value: ""})
+	return extras
+}
+
+// printSchema writes a schema of type t for the records produced by the worker
+// to out.
+func printSchema(ctx context.Context, out io.Writer, logger *zap.Logger, t signalio.SchemaType, scoringEnabled bool, scoringConfigFile, scoringColumn string, collectOpts []collector.Option) error {
+	// Both deps.dev backends produce the same signals, but only the API
+	// backend can be created without GCP credentials.
+	collectOpts = append(collectOpts, collector.DepsDevBackend(collector.DepsDevBackendAPI))
+	c, err := collector.New(ctx, logger, collectOpts...)
+	if err != nil {
+		return fmt.Errorf("collector: %w", err)
+	}
+	s, err := getScorer(logger, scoringEnabled, scoringConfigFile)
+	if err != nil {
+		return fmt.Errorf("scorer: %w", err)
+	}
+	if s != nil && scoringColumn == "" {
+		scoringColumn = s.Name()
+	}
+	return t.Write(out, c.EmptySets(), extraColumns(s != nil, scoringColumn)...)
+}
+
 func getScorer(logger *zap.Logger, scoringEnabled bool, scoringConfigFile string) (*scorer.Scorer, error) {
 	logger.Debug("Creating scorer")
 
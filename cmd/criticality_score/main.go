@@ -31,6 +31,8 @@ import (
 
 	"github.com/ossf/criticality_score/v2/cmd/criticality_score/inputiter"
 	"github.com/ossf/criticality_score/v2/internal/collector"
+	"github.com/ossf/criticality_score/v2/internal/collector/emaildomain"
+	"github.com/ossf/criticality_score/v2/internal/collector/gitclone"
 	log "github.com/ossf/criticality_score/v2/internal/log"
 	"github.com/ossf/criticality_score/v2/internal/outfile"
 	"github.com/ossf/criticality_score/v2/internal/scorer"
@@ -38,18 +40,54 @@ import (
 	"github.com/ossf/criticality_score/v2/internal/workerpool"
 )
 
-const defaultLogLevel = zapcore.InfoLevel
+const (
+	defaultLogLevel = zapcore.InfoLevel
+
+	// collectionErrorsColumnName is the name of the column listing the
+	// sources that failed for each repo.
+	collectionErrorsColumnName = "collection_errors"
+)
 
 var (
 	gcpProjectFlag        = flag.String("gcp-project-id", "", "the Google Cloud Project ID to use. Auto-detects by default.")
 	depsdevDisableFlag    = flag.Bool("depsdev-disable", false, "disables the collection of signals from deps.dev.")
 	depsdevDatasetFlag    = flag.String("depsdev-dataset", collector.DefaultGCPDatasetName, "the BigQuery dataset name to use.")
 	depsdevTTLFlag        = flag.Int("depsdev-expiration", 0, "the default expiration (`hours`) to use for deps.dev tables. No expiration by default.")
+	gitlabHostsFlag       = flag.String("gitlab-hosts", strings.Join(collector.DefaultGitLabHosts, ","), "a comma separated list of `hosts` to treat as GitLab instances.")
+	giteaHostsFlag        = flag.String("gitea-hosts", strings.Join(collector.DefaultGiteaHosts, ","), "a comma separated list of `hosts` to treat as Gitea or Forgejo instances.")
+	gitEnableFlag         = flag.Bool("git-enable", false, "enables the collection of signals by cloning repositories with git.")
+	gitSchemesFlag        = flag.String("git-schemes", strings.Join(gitclone.DefaultSchemes, ","), "a comma separated list of URL `schemes` for repositories that can be cloned with git.")
+	gitCacheDirFlag       = flag.String("git-cache-dir", "", "the `dir` used to store cloned repositories. Defaults to a directory in the user's cache.")
+	osvEnableFlag         = flag.Bool("osv-enable", false, "enables the collection of vulnerability signals from OSV.")
+	osvDataDirFlag        = flag.String("osv-data-dir", "", "a local `dir` of OSV vulnerability entries to use instead of the OSV API.")
+	osvLookbackFlag       = flag.Int("osv-lookback", 5, "the number of `years` used for counting recent vulnerabilities.")
+	scorecardEnableFlag   = flag.Bool("scorecard-enable", false, "enables the collection of signals from OpenSSF Scorecard.")
+	scorecardResultsFlag  = flag.String("scorecard-results", "", "a bucket `url` or local directory of Scorecard JSON results to use instead of the Scorecard API.")
+	downloadsEnableFlag   = flag.Bool("downloads-enable", false, "enables the collection of package download counts.")
+	contribEnableFlag     = flag.Bool("contributors-enable", false, "enables the collection of contributor signals for GitHub repositories.")
+	contribLookbackFlag   = flag.Int("contributors-lookback", 365, "the number of `days` of commit history used for contributor signals.")
+	orgAliasesFlag        = flag.String("org-domain-aliases", "", "a comma separated list of `from=to` email domain aliases used for organization signals.")
+	pullsEnableFlag       = flag.Bool("pulls-enable", false, "enables the collection of pull request signals for GitHub repositories.")
+	hygieneEnableFlag     = flag.Bool("hygiene-enable", false, "enables the collection of project hygiene signals for GitHub repositories.")
+	activityEnableFlag    = flag.Bool("commit-activity-enable", false, "enables the collection of weekly commit activity for GitHub repositories.")
+	languagesEnableFlag   = flag.Bool("languages-enable", false, "enables the collection of the language breakdown for GitHub repositories.")
+	releasesEnableFlag    = flag.Bool("releases-enable", false, "enables the collection of release signals for GitHub repositories.")
+	advisoriesEnableFlag  = flag.Bool("advisories-enable", false, "enables the collection of security advisory signals for GitHub repositories.")
+	dependentsEnableFlag  = flag.Bool("github-dependents-enable", false, "enables the collection of dependent counts from GitHub's dependency graph.")
+	triageEnableFlag      = flag.Bool("issue-triage-enable", false, "enables the collection of issue response time and backlog signals for GitHub repositories.")
+	failFastFlag          = flag.Bool("fail-fast", false, "stop when collecting signals for a repo fails, instead of leaving the failed signals unset.")
+	redirectForksFlag     = flag.Bool("redirect-forks", false, "collect signals for the parent of a repository that is a fork, instead of the fork.")
 	scoringDisableFlag    = flag.Bool("scoring-disable", false, "disables the generation of scores.")
 	scoringConfigFlag     = flag.String("scoring-config", "", "path to a YAML file for configuring the scoring algorithm.")
 	scoringColumnNameFlag = flag.String("scoring-column", "", "manually specify the name for the column used to hold the score.")
 	workersFlag           = flag.Int("workers", 1, "the total number of concurrent workers to use.")
+	sourceConcurrencyFlag = flag.Int("source-concurrency", collector.DefaultSourceConcurrency, "the number of sources each worker collects signals from concurrently.")
+	sourceTimeoutFlag     = flag.Duration("source-timeout", 0, "the maximum `duration` to spend collecting signals from each source for a repo. No limit by default.")
+	repoTimeoutFlag       = flag.Duration("repo-timeout", 0, "the maximum `duration` to spend collecting signals from all sources for a repo. No limit by default.")
 	versionFlag           = flag.Bool("version", false, "display the version of this command.")
+	listSignalsFlag       = flag.Bool("list-signals", false, "print a description of each signal that will be collected.")
+	printSchemaFlag       = flag.String("print-schema", "", "print a schema of `type` json-schema, bigquery-json or bigquery-csv for the output.")
+	depsdevBackend        = collector.DepsDevBackendBigQuery
 	logLevel              = defaultLogLevel
 	logEnv                log.Env
 	formatType            signalio.WriterType
@@ -57,6 +95,7 @@ var (
 
 // initFlags prepares any runtime flags, usage information and parses the flags.
 func initFlags() {
+	flag.TextVar(&depsdevBackend, "depsdev-backend", collector.DepsDevBackendBigQuery, "set how signals are collected from deps.dev. Choices are bigquery or api.")
 	flag.Var(&logLevel, "log", "set the `level` of logging.")
 	flag.TextVar(&logEnv, "log-env", log.DefaultEnv, "set logging `env`.")
 	flag.TextVar(&formatType, "format", signalio.WriterTypeText, "set the output format. Choices are text, json or csv.")
@@ -140,8 +179,20 @@ func main() {
 	s := getScorer(logger)
 	scoreColumnName := generateScoreColumnName(s)
 
+	var schemaType signalio.SchemaType
+	if *printSchemaFlag != "" {
+		if err := schemaType.UnmarshalText([]byte(*printSchemaFlag)); err != nil {
+			logger.With(
+				zap.Error(err),
+				zap.String("type", *printSchemaFlag),
+			).Error("Unknown schema type")
+			os.Exit(2)
+		}
+	}
+	describeOnly := *listSignalsFlag || *printSchemaFlag != ""
+
 	// Complete the validation of args
-	if flag.NArg() == 0 {
+	if flag.NArg() == 0 && !describeOnly {
 		logger.Error("An input file or at least one repo must be specified.")
 		os.Exit(2)
 	}
@@ -156,10 +207,90 @@ func main() {
 		collector.GCPProject(*gcpProjectFlag),
 		collector.GCPDatasetName(*depsdevDatasetFlag),
 		collector.GCPDatasetTTL(time.Hour * time.Duration(*depsdevTTLFlag)),
+		collector.DepsDevBackend(depsdevBackend),
+		collector.OSVDataDir(*osvDataDirFlag),
+		collector.OSVLookback(time.Duration(*osvLookbackFlag) * 365 * 24 * time.Hour),
+		collector.ScorecardResults(*scorecardResultsFlag),
+		collector.ContributorsLookback(time.Duration(*contribLookbackFlag) * 24 * time.Hour),
+		collector.GitLabHosts(strings.Split(*gitlabHostsFlag, ",")...),
+		collector.GiteaHosts(strings.Split(*giteaHostsFlag, ",")...),
+		collector.GitURLSchemes(strings.Split(*gitSchemesFlag, ",")...),
+	}
+	if *orgAliasesFlag != "" {
+		aliases, err := emaildomain.ParseAliases(*orgAliasesFlag)
+		if err != nil {
+			logger.With(
+				zap.Error(err),
+			).Error("Failed to parse org domain aliases")
+			os.Exit(2)
+		}
+		opts = append(opts, collector.OrgDomainAliases(aliases))
 	}
 	if *depsdevDisableFlag {
 		opts = append(opts, collector.DisableSource(collector.SourceTypeDepsDev))
 	}
+	if *osvEnableFlag {
+		opts = append(opts, collector.EnableSource(collector.SourceTypeOSV))
+	}
+	if *scorecardEnableFlag {
+		opts = append(opts, collector.EnableSource(collector.SourceTypeScorecard))
+	}
+	if *downloadsEnableFlag {
+		opts = append(opts, collector.EnableSource(collector.SourceTypeDownloads))
+	}
+	if *contribEnableFlag {
+		opts = append(opts, collector.EnableSource(collector.SourceTypeGitHubContributors))
+	}
+	if *pullsEnableFlag {
+		opts = append(opts, collector.EnableSource(collector.SourceTypeGitHubPulls))
+	}
+	if *hygieneEnableFlag {
+		opts = append(opts, collector.EnableSource(collector.SourceTypeGitHubHygiene))
+	}
+	if *gitEnableFlag {
+		opts = append(opts, collector.EnableSource(collector.SourceTypeGitClone))
+	}
+	if *activityEnableFlag {
+		opts = append(opts, collector.EnableSource(collector.SourceTypeGitHubCommitActivity))
+	}
+	if *languagesEnableFlag {
+		opts = append(opts, collector.EnableSource(collector.SourceTypeGitHubLanguages))
+	}
+	if *releasesEnableFlag {
+		opts = append(opts, collector.EnableSource(collector.SourceTypeGitHubReleases))
+	}
+	if *advisoriesEnableFlag {
+		opts = append(opts, collector.EnableSource(collector.SourceTypeGitHubAdvisories))
+	}
+	if *triageEnableFlag {
+		opts = append(opts, collector.EnableSource(collector.SourceTypeGitHubIssueTriage))
+	}
+	if *dependentsEnableFlag {
+		// The dependents page is slow and scraped from the GitHub website, so
+		// it is only collected when requested.
+		opts = append(opts, collector.EnableSource(collector.SourceTypeGitHubDependents))
+	}
+	opts = append(opts, collector.SourceConcurrency(*sourceConcurrencyFlag))
+	if *sourceTimeoutFlag > 0 {
+		opts = append(opts, collector.SourceTimeout(*sourceTimeoutFlag))
+	}
+	if *repoTimeoutFlag > 0 {
+		opts = append(opts, collector.RepoTimeout(*repoTimeoutFlag))
+	}
+	if *failFastFlag {
+		opts = append(opts, collector.FailFast())
+	}
+	if *redirectForksFlag {
+		opts = append(opts, collector.RedirectForks())
+	}
+	if *gitCacheDirFlag != "" {
+		opts = append(opts, collector.GitCacheDir(*gitCacheDirFlag))
+	}
+	if describeOnly {
+		// Both deps.dev backends produce the same signals, but only the API
+		// backend can be created without GCP credentials.
+		opts = append(opts, collector.DepsDevBackend(collector.DepsDevBackendAPI))
+	}
 
 	c, err := collector.New(ctx, logger, opts...)
 	if err != nil {
@@ -169,6 +300,31 @@ func main() {
 		os.Exit(2)
 	}
 
+	if *listSignalsFlag {
+		if err := printSignals(os.Stdout, c.EmptySets()); err != nil {
+			logger.With(
+				zap.Error(err),
+			).Error("Failed to print signals")
+			os.Exit(1)
+		}
+		return
+	}
+	if *printSchemaFlag != "" {
+		var extras []signalio.Field
+		if s != nil {
+			// The score is written as a formatted string.
+			extras = append(extras, signalio.Field{Key: scoreColumnName, Value: ""})
+		}
+		extras = append(extras, signalio.Field{Key: collectionErrorsColumnName, Value: ""})
+		if err := schemaType.Write(os.Stdout, c.EmptySets(), extras...); err != nil {
+			logger.With(
+				zap.Error(err),
+			).Error("Failed to print schema")
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Prepare the input for reading
 	iter, err := inputiter.New(flag.Args())
 	if err != nil {
@@ -194,6 +350,7 @@ func main() {
 	if s != nil {
 		extras = append(extras, scoreColumnName)
 	}
+	extras = append(extras, collectionErrorsColumnName)
 	out := formatType.New(w, c.EmptySets(), extras...)
 
 	// Start the workers that process a channel of repo urls.
@@ -203,13 +360,20 @@ func main() {
 		for u := range repos {
 			l := innerLogger.With(zap.String("url", u.String()))
 			ss, err := c.Collect(ctx, u, "")
-			if err != nil {
-				if errors.Is(err, collector.ErrUncollectableRepo) {
-					l.With(
-						zap.Error(err),
-					).Warn("Repo cannot be collected")
-					return
-				}
+			var collectErrs collector.CollectionErrors
+			switch {
+			case errors.As(err, &collectErrs):
+				// The signals from the sources that succeeded are still
+				// written, with the failures recorded alongside them.
+				l.With(
+					zap.Error(err),
+				).Warn("Failed to collect some signals for repo")
+			case errors.Is(err, collector.ErrUncollectableRepo):
+				l.With(
+					zap.Error(err),
+				).Warn("Repo cannot be collected")
+				continue
+			case err != nil:
 				l.With(
 					zap.Error(err),
 				).Error("Failed to collect signals for repo")
@@ -226,6 +390,13 @@ func main() {
 				extras = append(extras, f)
 			}
 
+			// Record which sources failed, if any.
+			f := signalio.Field{Key: collectionErrorsColumnName}
+			if collectErrs != nil {
+				f.Value = collectErrs.Error()
+			}
+			extras = append(extras, f)
+
 			// Write the signals to storage.
 			if err := out.WriteSignals(ss, extras...); err != nil {
 				l.With(
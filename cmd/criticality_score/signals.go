@@ -0,0 +1,49 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/ossf/criticality_score/v2/internal/collector/signal"
+)
+
+// printSignals writes a table describing each of the signals in sets to w.
+func printSignals(w io.Writer, sets []signal.Set) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tTYPE\tUNIT\tLOOKBACK\tSOURCE\tDESCRIPTION")
+	for _, s := range sets {
+		for _, d := range signal.Describe(s) {
+			name := d.Name
+			if d.Detail {
+				// Details are only present in JSON output.
+				name += " (json)"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				name, d.Type.String(), orDash(d.Unit), orDash(d.Lookback), orDash(d.Source), d.Description)
+		}
+	}
+	return tw.Flush()
+}
+
+// orDash returns s, or "-" if s is empty, so columns are not left blank.
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
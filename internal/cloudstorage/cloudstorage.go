@@ -86,3 +86,31 @@ func NewWriter(ctx context.Context, rawURL string) (io.WriteCloser, error) {
 	}
 	return w, nil
 }
+
+// OpenBucket opens the bucket or local directory at rawURL for reading.
+//
+// Any path in rawURL is used as a prefix, so the keys of the returned bucket
+// are relative to rawURL.
+func OpenBucket(ctx context.Context, rawURL string) (*blob.Bucket, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("url parse: %w", err)
+	}
+	// Ensure the whole path is treated as a directory, rather than splitting
+	// off the last element as a prefix.
+	if !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+	}
+	bucket, prefix, err := parseBucketAndPrefix(u.String())
+	if err != nil {
+		return nil, err
+	}
+	b, err := blob.OpenBucket(ctx, bucket)
+	if err != nil {
+		return nil, fmt.Errorf("failed opening %s: %w", bucket, err)
+	}
+	if prefix != "" {
+		b = blob.PrefixedBucket(b, prefix)
+	}
+	return b, nil
+}
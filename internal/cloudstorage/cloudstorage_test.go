@@ -17,6 +17,8 @@ package cloudstorage
 import (
 	"context"
 	"net/url"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -108,3 +110,27 @@ func assertBucket(t *testing.T, bucket, wantScheme, wantHost, wantPath string, w
 		}
 	}
 }
+
+func TestOpenBucket(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "path", "to"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "path", "to", "file"), []byte("data"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := OpenBucket(ctx, filepath.Join(dir, "path"))
+	if err != nil {
+		t.Fatalf("OpenBucket() = %v, want no error", err)
+	}
+	defer b.Close()
+	got, err := b.ReadAll(ctx, "to/file")
+	if err != nil {
+		t.Fatalf("ReadAll() = %v, want no error", err)
+	}
+	if string(got) != "data" {
+		t.Fatalf("ReadAll() = %q, want %q", got, "data")
+	}
+}
@@ -21,14 +21,23 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"strings"
 
 	"go.uber.org/zap"
 
 	"github.com/ossf/criticality_score/v2/internal/collector/depsdev"
+	"github.com/ossf/criticality_score/v2/internal/collector/downloads"
+	"github.com/ossf/criticality_score/v2/internal/collector/gitclone"
+	"github.com/ossf/criticality_score/v2/internal/collector/gitea"
 	"github.com/ossf/criticality_score/v2/internal/collector/github"
+	"github.com/ossf/criticality_score/v2/internal/collector/githubdependents"
 	"github.com/ossf/criticality_score/v2/internal/collector/githubmentions"
+	"github.com/ossf/criticality_score/v2/internal/collector/gitlab"
+	"github.com/ossf/criticality_score/v2/internal/collector/osv"
 	"github.com/ossf/criticality_score/v2/internal/collector/projectrepo"
+	"github.com/ossf/criticality_score/v2/internal/collector/scorecard"
 	"github.com/ossf/criticality_score/v2/internal/collector/signal"
+	"github.com/ossf/criticality_score/v2/internal/depsdevapi"
 	"github.com/ossf/criticality_score/v2/internal/githubapi"
 )
 
@@ -39,6 +48,40 @@ import (
 // may point to a repo that is inaccessible or missing.
 var ErrUncollectableRepo = errors.New("repo failed")
 
+// SourceError records the failure of a single Source while collecting the
+// signals for a repository.
+type SourceError struct {
+	Namespace signal.Namespace
+	Err       error
+}
+
+// Error implements the error interface.
+func (e *SourceError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Namespace, e.Err)
+}
+
+// Unwrap returns the error returned by the Source.
+func (e *SourceError) Unwrap() error {
+	return e.Err
+}
+
+// CollectionErrors is returned by Collect along with the signals that were
+// collected when one or more Sources failed.
+type CollectionErrors []*SourceError
+
+// Error implements the error interface.
+func (e CollectionErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// maxForkRedirects limits the number of times collection will be redirected
+// from a fork to its parent.
+const maxForkRedirects = 5
+
 type Collector struct {
 	config   *config
 	logger   *zap.Logger
@@ -47,37 +90,108 @@ type Collector struct {
 }
 
 func New(ctx context.Context, logger *zap.Logger, opts ...Option) (*Collector, error) {
+	cfg := makeConfig(ctx, logger, opts...)
 	c := &Collector{
-		config:   makeConfig(ctx, logger, opts...),
+		config:   cfg,
 		logger:   logger,
 		resolver: &projectrepo.Resolver{},
-		registry: newRegistry(),
+		registry: newRegistry(cfg),
 	}
 
 	ghClient := githubapi.NewClient(c.config.gitHubHTTPClient)
+	ddClient := depsdevapi.NewClient(c.config.depsDevClient, depsdevapi.DefaultBaseURL)
 
 	// Register all the Repo factories.
 	c.resolver.Register(github.NewRepoFactory(ghClient, logger))
+	c.resolver.Register(gitlab.NewRepoFactory(c.config.gitLabHTTPClient, logger, c.config.gitLabHosts))
+	c.resolver.Register(gitea.NewRepoFactory(c.config.giteaHTTPClient, logger, c.config.giteaHosts))
+	if c.config.IsEnabled(SourceTypeGitClone) {
+		// The git clone factory matches any URL, so it must be registered last.
+		c.resolver.Register(gitclone.NewRepoFactory(logger, c.config.gitCacheDir, c.config.gitURLSchemes))
+	}
 
 	// Register all the sources that are supported and enabled.
 	if c.config.IsEnabled(SourceTypeGithubRepo) {
 		c.registry.Register(&github.RepoSource{})
 	}
 	if c.config.IsEnabled(SourceTypeGithubIssues) {
-		c.registry.Register(&github.IssuesSource{})
+		c.registry.Register(github.NewIssuesSource(c.config.IsEnabled(SourceTypeGitHubIssueTriage)))
+	}
+	if c.config.IsEnabled(SourceTypeGitHubContributors) {
+		c.registry.Register(github.NewContributorsSource(c.config.contribLookback, c.config.orgAliases))
+	}
+	if c.config.IsEnabled(SourceTypeGitHubPulls) {
+		c.registry.Register(&github.PullsSource{})
+	}
+	if c.config.IsEnabled(SourceTypeGitHubHygiene) {
+		c.registry.Register(&github.HygieneSource{})
+	}
+	if c.config.IsEnabled(SourceTypeGitHubCommitActivity) {
+		c.registry.Register(&github.CommitActivitySource{})
+	}
+	if c.config.IsEnabled(SourceTypeGitHubLanguages) {
+		c.registry.Register(&github.LanguagesSource{})
+	}
+	if c.config.IsEnabled(SourceTypeGitHubReleases) {
+		c.registry.Register(&github.ReleasesSource{})
+	}
+	if c.config.IsEnabled(SourceTypeGitHubAdvisories) {
+		c.registry.Register(&github.AdvisoriesSource{})
+	}
+	if c.config.IsEnabled(SourceTypeGitLabRepo) {
+		c.registry.Register(&gitlab.RepoSource{})
+	}
+	if c.config.IsEnabled(SourceTypeGitLabIssues) {
+		c.registry.Register(&gitlab.IssuesSource{})
+	}
+	if c.config.IsEnabled(SourceTypeGiteaRepo) {
+		c.registry.Register(&gitea.RepoSource{})
+	}
+	if c.config.IsEnabled(SourceTypeGiteaIssues) {
+		c.registry.Register(&gitea.IssuesSource{})
+	}
+	if c.config.IsEnabled(SourceTypeGitClone) {
+		c.registry.Register(&gitclone.Source{})
 	}
 	if c.config.IsEnabled(SourceTypeGitHubMentions) {
 		c.registry.Register(githubmentions.NewSource(ghClient))
 	}
+	if c.config.IsEnabled(SourceTypeGitHubDependents) {
+		c.registry.Register(githubdependents.NewSource(logger, c.config.dependentsClient, githubdependents.DefaultBaseURL))
+	}
+	if c.config.IsEnabled(SourceTypeOSV) {
+		if c.config.osvDataDir != "" {
+			c.registry.Register(osv.NewDumpSource(logger, ddClient, c.config.osvDataDir, c.config.osvLookback))
+		} else {
+			c.registry.Register(osv.NewSource(logger, ddClient, c.config.osvHTTPClient, osv.DefaultAPIURL, c.config.osvLookback))
+		}
+	}
+	if c.config.IsEnabled(SourceTypeScorecard) {
+		if c.config.scorecardResults != "" {
+			scsource, err := scorecard.NewBucketSource(ctx, logger, c.config.scorecardResults)
+			if err != nil {
+				return nil, fmt.Errorf("init scorecard source: %w", err)
+			}
+			c.registry.Register(scsource)
+		} else {
+			c.registry.Register(scorecard.NewSource(logger, c.config.scorecardHTTPClient, scorecard.DefaultAPIURL))
+		}
+	}
+	if c.config.IsEnabled(SourceTypeDownloads) {
+		c.registry.Register(downloads.NewSource(logger, ddClient, c.config.downloadsHTTPClient))
+	}
 	if !c.config.IsEnabled(SourceTypeDepsDev) {
 		// deps.dev collection source has been disabled, so skip it.
 		logger.Warn("deps.dev signal source is disabled.")
+	} else if c.config.depsDevBackend == DepsDevBackendAPI {
+		logger.Info("deps.dev signal source enabled", zap.Stringer("backend", c.config.depsDevBackend))
+		c.registry.Register(depsdev.NewAPISource(logger, ddClient))
 	} else {
 		ddsource, err := depsdev.NewSource(ctx, logger, c.config.gcpProject, c.config.gcpDatasetName, c.config.gcpDatasetTTL)
 		if err != nil {
 			return nil, fmt.Errorf("init deps.dev source: %w", err)
 		}
-		logger.Info("deps.dev signal source enabled")
+		logger.Info("deps.dev signal source enabled", zap.Stringer("backend", c.config.depsDevBackend))
 		c.registry.Register(ddsource)
 	}
 
@@ -94,9 +208,50 @@ func (c *Collector) EmptySets() []signal.Set {
 //
 // An optional jobID can be specified which can be used by underlying sources to
 // manage caching. For simple usage this can be the empty string.
+//
+// If some Sources fail, the signals from those Sources are left unset and the
+// partial results are returned with a CollectionErrors error describing each
+// failure. If the FailFast option is used, the first failure is returned as
+// the error without any signals instead. Sources that exceed the SourceTimeout
+// or RepoTimeout are always reported in CollectionErrors.
 func (c *Collector) Collect(ctx context.Context, u *url.URL, jobID string) ([]signal.Set, error) {
 	l := c.config.logger.With(zap.String("url", u.String()))
 
+	repo, err := c.resolve(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; c.config.redirectForks && i < maxForkRedirects; i++ {
+		fork, ok := repo.(projectrepo.Fork)
+		if !ok {
+			break
+		}
+		parent := fork.ParentURL()
+		if parent == nil {
+			break
+		}
+		l.Info("Redirecting fork to parent", zap.String("parent_url", parent.String()))
+		if repo, err = c.resolve(ctx, parent); err != nil {
+			return nil, err
+		}
+	}
+	l = l.With(zap.String("canonical_url", repo.URL().String()))
+
+	l.Info("Collecting")
+	ss, err := c.registry.Collect(ctx, repo, jobID)
+	var errs CollectionErrors
+	if errors.As(err, &errs) {
+		l.With(zap.Error(err)).Warn("Some signals failed to collect")
+		return ss, errs
+	}
+	if err != nil {
+		return nil, fmt.Errorf("collecting project: %w", err)
+	}
+	return ss, nil
+}
+
+// resolve returns the project repo for the given url.
+func (c *Collector) resolve(ctx context.Context, u *url.URL) (projectrepo.Repo, error) {
 	repo, err := c.resolver.Resolve(ctx, u)
 	if err != nil {
 		switch {
@@ -111,12 +266,5 @@ func (c *Collector) Collect(ctx context.Context, u *url.URL, jobID string) ([]si
 			return nil, fmt.Errorf("resolving project: %w", err)
 		}
 	}
-	l = l.With(zap.String("canonical_url", repo.URL().String()))
-
-	l.Info("Collecting")
-	ss, err := c.registry.Collect(ctx, repo, jobID)
-	if err != nil {
-		return nil, fmt.Errorf("collecting project: %w", err)
-	}
-	return ss, nil
+	return repo, nil
 }
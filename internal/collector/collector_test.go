@@ -0,0 +1,56 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+
+	"github.com/ossf/criticality_score/v2/internal/collector/signal"
+)
+
+func TestEmptySetsAreDescribed(t *testing.T) {
+	c, err := New(context.Background(), zaptest.NewLogger(t),
+		append(optInOptions(),
+			EnableAllSources(),
+			DepsDevBackend(DepsDevBackendAPI),
+			GitCacheDir(t.TempDir()),
+		)...,
+	)
+	if err != nil {
+		t.Fatalf("New() = %v, want no error", err)
+	}
+	for _, s := range c.EmptySets() {
+		for _, d := range signal.Describe(s) {
+			if d.Description == "" {
+				t.Errorf("%s has no description", d.Name)
+			}
+			if d.Source == "" {
+				t.Errorf("%s has no source", d.Name)
+			}
+		}
+	}
+}
+
+// optInOptions returns the Options that enable every opt-in SourceType.
+func optInOptions() []Option {
+	var opts []Option
+	for _, s := range optInSourceTypes {
+		opts = append(opts, EnableSource(s))
+	}
+	return opts
+}
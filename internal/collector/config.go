@@ -16,8 +16,11 @@ package collector
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/go-logr/zapr"
@@ -25,12 +28,33 @@ import (
 	sclog "github.com/ossf/scorecard/v4/log"
 	"go.uber.org/zap"
 
+	"github.com/ossf/criticality_score/v2/internal/collector/downloads"
+	"github.com/ossf/criticality_score/v2/internal/collector/emaildomain"
+	"github.com/ossf/criticality_score/v2/internal/collector/gitclone"
+	"github.com/ossf/criticality_score/v2/internal/collector/gitea"
+	"github.com/ossf/criticality_score/v2/internal/collector/github"
+	"github.com/ossf/criticality_score/v2/internal/collector/gitlab"
+	"github.com/ossf/criticality_score/v2/internal/collector/osv"
+	"github.com/ossf/criticality_score/v2/internal/collector/scorecard"
+	"github.com/ossf/criticality_score/v2/internal/depsdevapi"
 	"github.com/ossf/criticality_score/v2/internal/githubapi"
 )
 
 // DefaultGCPDatasetName is the default name to use for GCP BigQuery Datasets.
 const DefaultGCPDatasetName = "criticality_score_data"
 
+// DefaultSourceConcurrency is the default number of Sources run at the same
+// time when collecting signals for a single repository.
+const DefaultSourceConcurrency = 4
+
+// DefaultGitLabHosts is the default set of hostnames that are treated as
+// GitLab instances.
+var DefaultGitLabHosts = []string{"gitlab.com"}
+
+// DefaultGiteaHosts is the default set of hostnames that are treated as Gitea
+// or Forgejo instances.
+var DefaultGiteaHosts = []string{"codeberg.org"}
+
 // SourceType is used to identify the various sources signals can be collected
 // from.
 type SourceType int
@@ -40,6 +64,25 @@ const (
 	SourceTypeGithubIssues
 	SourceTypeGitHubMentions
 	SourceTypeDepsDev
+	SourceTypeGitLabRepo
+	SourceTypeGitLabIssues
+	SourceTypeGiteaRepo
+	SourceTypeGiteaIssues
+	SourceTypeGitClone
+	SourceTypeOSV
+	SourceTypeScorecard
+	SourceTypeDownloads
+	SourceTypeGitHubContributors
+	SourceTypeGitHubPulls
+	SourceTypeGitHubHygiene
+	SourceTypeGitHubCommitActivity
+	SourceTypeGitHubLanguages
+	SourceTypeGitHubReleases
+	SourceTypeGitHubAdvisories
+	SourceTypeGitHubDependents
+	// SourceTypeGitHubIssueTriage adds the issue triage signals to the
+	// signals collected by SourceTypeGithubIssues.
+	SourceTypeGitHubIssueTriage
 )
 
 // String implements the fmt.Stringer interface.
@@ -53,11 +96,120 @@ func (t SourceType) String() string {
 		return "SourceTypeGitHubMentions"
 	case SourceTypeDepsDev:
 		return "SourceTypeDepsDev"
+	case SourceTypeGitLabRepo:
+		return "SourceTypeGitLabRepo"
+	case SourceTypeGitLabIssues:
+		return "SourceTypeGitLabIssues"
+	case SourceTypeGiteaRepo:
+		return "SourceTypeGiteaRepo"
+	case SourceTypeGiteaIssues:
+		return "SourceTypeGiteaIssues"
+	case SourceTypeGitClone:
+		return "SourceTypeGitClone"
+	case SourceTypeOSV:
+		return "SourceTypeOSV"
+	case SourceTypeScorecard:
+		return "SourceTypeScorecard"
+	case SourceTypeDownloads:
+		return "SourceTypeDownloads"
+	case SourceTypeGitHubContributors:
+		return "SourceTypeGitHubContributors"
+	case SourceTypeGitHubPulls:
+		return "SourceTypeGitHubPulls"
+	case SourceTypeGitHubHygiene:
+		return "SourceTypeGitHubHygiene"
+	case SourceTypeGitHubCommitActivity:
+		return "SourceTypeGitHubCommitActivity"
+	case SourceTypeGitHubLanguages:
+		return "SourceTypeGitHubLanguages"
+	case SourceTypeGitHubReleases:
+		return "SourceTypeGitHubReleases"
+	case SourceTypeGitHubAdvisories:
+		return "SourceTypeGitHubAdvisories"
+	case SourceTypeGitHubDependents:
+		return "SourceTypeGitHubDependents"
+	case SourceTypeGitHubIssueTriage:
+		return "SourceTypeGitHubIssueTriage"
 	default:
 		return fmt.Sprintf("Unknown SourceType %d", int(t))
 	}
 }
 
+// DepsDevBackendType is used to identify how signals are collected from
+// deps.dev.
+type DepsDevBackendType int
+
+const (
+	// DepsDevBackendBigQuery collects deps.dev signals from the public
+	// BigQuery dataset. This requires a GCP project.
+	DepsDevBackendBigQuery DepsDevBackendType = iota
+
+	// DepsDevBackendAPI collects deps.dev signals from the public deps.dev
+	// API. No credentials are required.
+	DepsDevBackendAPI
+)
+
+// ErrUnknownDepsDevBackend is returned when a DepsDevBackendType is not
+// recognized.
+var ErrUnknownDepsDevBackend = errors.New("unknown deps.dev backend")
+
+// String implements the fmt.Stringer interface.
+func (t DepsDevBackendType) String() string {
+	text, err := t.MarshalText()
+	if err != nil {
+		return ""
+	}
+	return string(text)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (t DepsDevBackendType) MarshalText() ([]byte, error) {
+	switch t {
+	case DepsDevBackendBigQuery:
+		return []byte("bigquery"), nil
+	case DepsDevBackendAPI:
+		return []byte("api"), nil
+	default:
+		return []byte{}, ErrUnknownDepsDevBackend
+	}
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (t *DepsDevBackendType) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "bigquery":
+		*t = DepsDevBackendBigQuery
+	case "api":
+		*t = DepsDevBackendAPI
+	default:
+		return ErrUnknownDepsDevBackend
+	}
+	return nil
+}
+
+// optInSourceTypes are disabled by default, even if EnableAllSources is used.
+// They are only collected if they are enabled with EnableSource.
+//
+// Sources added after the original set are opt-in, so that adding a source
+// does not silently change the cost or the output of an existing run.
+var optInSourceTypes = []SourceType{
+	SourceTypeOSV,
+	SourceTypeScorecard,
+	SourceTypeDownloads,
+	SourceTypeGitHubContributors,
+	SourceTypeGitHubPulls,
+	SourceTypeGitHubHygiene,
+	SourceTypeGitHubCommitActivity,
+	SourceTypeGitHubLanguages,
+	SourceTypeGitHubReleases,
+	SourceTypeGitHubAdvisories,
+	SourceTypeGitHubIssueTriage,
+	// The dependents are scraped from the GitHub website, which is slow.
+	SourceTypeGitHubDependents,
+	// Any URL not matched by another factory is cloned with git.
+	SourceTypeGitClone,
+}
+
 type sourceStatus int
 
 const (
@@ -69,7 +221,36 @@ const (
 type config struct {
 	logger *zap.Logger
 
-	gitHubHTTPClient *http.Client
+	gitHubHTTPClient    *http.Client
+	gitLabHTTPClient    *http.Client
+	giteaHTTPClient     *http.Client
+	depsDevClient       *http.Client
+	osvHTTPClient       *http.Client
+	scorecardHTTPClient *http.Client
+	downloadsHTTPClient *http.Client
+	dependentsClient    *http.Client
+
+	gitLabHosts []string
+	giteaHosts  []string
+
+	gitCacheDir   string
+	gitURLSchemes []string
+
+	contribLookback time.Duration
+	orgAliases      emaildomain.Aliases
+
+	redirectForks     bool
+	failFast          bool
+	sourceConcurrency int
+	sourceTimeout     time.Duration
+	repoTimeout       time.Duration
+
+	osvDataDir  string
+	osvLookback time.Duration
+
+	scorecardResults string
+
+	depsDevBackend DepsDevBackendType
 
 	gcpProject     string
 	gcpDatasetName string
@@ -94,9 +275,28 @@ func makeConfig(ctx context.Context, logger *zap.Logger, opts ...Option) *config
 		defaultSourceStatus: sourceStatusEnabled,
 		sourceStatuses:      make(map[SourceType]sourceStatus),
 		gitHubHTTPClient:    defaultGitHubHTTPClient(ctx, logger),
+		gitLabHTTPClient:    defaultGitLabHTTPClient(),
+		gitLabHosts:         DefaultGitLabHosts,
+		giteaHTTPClient:     defaultGiteaHTTPClient(),
+		depsDevClient:       defaultDepsDevHTTPClient(),
+		depsDevBackend:      DepsDevBackendBigQuery,
+		giteaHosts:          DefaultGiteaHosts,
+		gitCacheDir:         defaultGitCacheDir(),
+		gitURLSchemes:       gitclone.DefaultSchemes,
+		contribLookback:     github.DefaultContributorsLookback,
+		osvHTTPClient:       defaultOSVHTTPClient(),
+		osvLookback:         osv.DefaultLookback,
+		scorecardHTTPClient: defaultScorecardHTTPClient(),
+		downloadsHTTPClient: defaultDownloadsHTTPClient(),
+		dependentsClient:    defaultDependentsHTTPClient(logger),
 		gcpProject:          "",
 		gcpDatasetName:      DefaultGCPDatasetName,
 		gcpDatasetTTL:       time.Duration(0),
+		sourceConcurrency:   DefaultSourceConcurrency,
+	}
+
+	for _, s := range optInSourceTypes {
+		c.sourceStatuses[s] = sourceStatusDisabled
 	}
 
 	for _, opt := range opts {
@@ -128,10 +328,62 @@ func defaultGitHubHTTPClient(ctx context.Context, logger *zap.Logger) *http.Clie
 	}
 }
 
+func defaultGitLabHTTPClient() *http.Client {
+	return &http.Client{
+		Transport: gitlab.NewTransport(http.DefaultTransport),
+	}
+}
+
+func defaultGiteaHTTPClient() *http.Client {
+	return &http.Client{
+		Transport: gitea.NewTransport(http.DefaultTransport),
+	}
+}
+
+func defaultDepsDevHTTPClient() *http.Client {
+	return &http.Client{
+		Transport: depsdevapi.NewTransport(http.DefaultTransport),
+	}
+}
+
+func defaultOSVHTTPClient() *http.Client {
+	return &http.Client{
+		Transport: osv.NewTransport(http.DefaultTransport),
+	}
+}
+
+func defaultScorecardHTTPClient() *http.Client {
+	return &http.Client{
+		Transport: scorecard.NewTransport(http.DefaultTransport),
+	}
+}
+
+func defaultDownloadsHTTPClient() *http.Client {
+	return &http.Client{
+		Transport: downloads.NewTransport(http.DefaultTransport),
+	}
+}
+
+func defaultDependentsHTTPClient(logger *zap.Logger) *http.Client {
+	return &http.Client{
+		Transport: githubapi.NewRetryRoundTripper(http.DefaultTransport, logger),
+	}
+}
+
+func defaultGitCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "criticality_score", "git")
+}
+
 // EnableAllSources enables all SourceTypes for collection.
 //
 // All data sources will be used for collection unless explicitly disabled
-// with DisableSource.
+// with DisableSource, except for the opt-in SourceTypes added after the
+// original set, such as SourceTypeOSV and SourceTypeGitClone, which must be
+// enabled with EnableSource.
 func EnableAllSources() Option {
 	return option(func(c *config) {
 		c.defaultSourceStatus = sourceStatusEnabled
@@ -186,3 +438,164 @@ func GCPDatasetTTL(ttl time.Duration) Option {
 		c.gcpDatasetTTL = ttl
 	})
 }
+
+// DepsDevBackend sets how signals are collected from deps.dev.
+//
+// If not supplied, DepsDevBackendBigQuery is used.
+func DepsDevBackend(t DepsDevBackendType) Option {
+	return option(func(c *config) {
+		c.depsDevBackend = t
+	})
+}
+
+// RedirectForks causes collection to be redirected from a repository that is
+// a fork to the repository it was forked from.
+//
+// Signals are then collected for the parent repository rather than the fork.
+func RedirectForks() Option {
+	return option(func(c *config) {
+		c.redirectForks = true
+	})
+}
+
+// FailFast causes collection of a repository to stop at the first Source that
+// fails, returning its error instead of the partial results.
+func FailFast() Option {
+	return option(func(c *config) {
+		c.failFast = true
+	})
+}
+
+// SourceConcurrency limits the number of Sources run at the same time when
+// collecting signals for a single repository.
+//
+// If n is less than 1, DefaultSourceConcurrency is used.
+func SourceConcurrency(n int) Option {
+	return option(func(c *config) {
+		if n < 1 {
+			n = DefaultSourceConcurrency
+		}
+		c.sourceConcurrency = n
+	})
+}
+
+// SourceTimeout limits the time spent collecting signals from each Source for
+// a repository to d.
+//
+// A Source that takes longer has its signals left unset, and is reported as a
+// CollectionErrors error wrapping ErrSourceTimeout rather than failing
+// collection.
+//
+// If d is 0 Sources are not limited, which is the default.
+func SourceTimeout(d time.Duration) Option {
+	return option(func(c *config) {
+		c.sourceTimeout = d
+	})
+}
+
+// RepoTimeout limits the total time spent collecting signals from all the
+// Sources for a repository to d.
+//
+// Sources that have not finished by then are treated the same as Sources that
+// exceed the SourceTimeout.
+//
+// If d is 0 collection is not limited, which is the default.
+func RepoTimeout(d time.Duration) Option {
+	return option(func(c *config) {
+		c.repoTimeout = d
+	})
+}
+
+// GitLabHosts overrides DefaultGitLabHosts with the supplied hostnames.
+//
+// Repositories hosted on any of these hostnames will be collected using the
+// GitLab API. This can be used to support self-hosted GitLab instances.
+func GitLabHosts(hosts ...string) Option {
+	return option(func(c *config) {
+		c.gitLabHosts = hosts
+	})
+}
+
+// GiteaHosts overrides DefaultGiteaHosts with the supplied hostnames.
+//
+// Repositories hosted on any of these hostnames will be collected using the
+// Gitea API. This can be used to support Forgejo and self-hosted Gitea
+// instances.
+func GiteaHosts(hosts ...string) Option {
+	return option(func(c *config) {
+		c.giteaHosts = hosts
+	})
+}
+
+// GitCacheDir sets the directory used to store clones of repositories that
+// are collected by cloning them with git.
+//
+// Repositories already present in the directory are updated rather than
+// cloned again. If not supplied, a directory inside the user's cache directory
+// is used.
+func GitCacheDir(dir string) Option {
+	return option(func(c *config) {
+		c.gitCacheDir = dir
+	})
+}
+
+// GitURLSchemes overrides gitclone.DefaultSchemes with the supplied URL
+// schemes.
+//
+// Only repositories with URLs using one of these schemes are cloned when
+// SourceTypeGitClone is enabled. Add "file" or "ssh" with care, as they allow
+// the URLs being collected to reach local paths and SSH credentials.
+func GitURLSchemes(schemes ...string) Option {
+	return option(func(c *config) {
+		c.gitURLSchemes = schemes
+	})
+}
+
+// ContributorsLookback sets the period of commit history used for collecting
+// contributor signals.
+//
+// If not supplied, github.DefaultContributorsLookback is used.
+func ContributorsLookback(d time.Duration) Option {
+	return option(func(c *config) {
+		c.contribLookback = d
+	})
+}
+
+// OrgDomainAliases sets the aliases applied to the domains of commit author
+// email addresses before they are attributed to an organization.
+func OrgDomainAliases(a emaildomain.Aliases) Option {
+	return option(func(c *config) {
+		c.orgAliases = a
+	})
+}
+
+// OSVDataDir sets a local directory of OSV vulnerability entries to use
+// instead of the OSV API.
+//
+// Every file ending in ".json" below the directory is read, so the directory
+// can contain the extracted all.zip files for several ecosystems.
+func OSVDataDir(dir string) Option {
+	return option(func(c *config) {
+		c.osvDataDir = dir
+	})
+}
+
+// OSVLookback sets the period used for counting recent vulnerabilities.
+//
+// If not supplied, osv.DefaultLookback is used.
+func OSVLookback(d time.Duration) Option {
+	return option(func(c *config) {
+		c.osvLookback = d
+	})
+}
+
+// ScorecardResults sets a bucket URL or local directory of Scorecard JSON
+// results to use instead of the Scorecard API.
+//
+// The result for each repository must be stored in a file named after the
+// repository in lowercase, such as "github.com/ossf/scorecard.json".
+func ScorecardResults(rawURL string) Option {
+	return option(func(c *config) {
+		c.scorecardResults = rawURL
+	})
+}
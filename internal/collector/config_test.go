@@ -16,10 +16,15 @@ package collector
 
 import (
 	"context"
+	"errors"
+	"reflect"
 	"testing"
 	"time"
 
 	"go.uber.org/zap/zaptest"
+
+	"github.com/ossf/criticality_score/v2/internal/collector/emaildomain"
+	"github.com/ossf/criticality_score/v2/internal/collector/gitclone"
 )
 
 var allSourceTypes = []SourceType{
@@ -27,12 +32,53 @@ var allSourceTypes = []SourceType{
 	SourceTypeGithubIssues,
 	SourceTypeGitHubMentions,
 	SourceTypeDepsDev,
+	SourceTypeGitLabRepo,
+	SourceTypeGitLabIssues,
+	SourceTypeGiteaRepo,
+	SourceTypeGiteaIssues,
+	SourceTypeGitClone,
+	SourceTypeOSV,
+	SourceTypeScorecard,
+	SourceTypeDownloads,
+	SourceTypeGitHubContributors,
+	SourceTypeGitHubPulls,
+	SourceTypeGitHubHygiene,
+	SourceTypeGitHubCommitActivity,
+	SourceTypeGitHubLanguages,
+	SourceTypeGitHubReleases,
+	SourceTypeGitHubAdvisories,
+	SourceTypeGitHubDependents,
+	SourceTypeGitHubIssueTriage,
+}
+
+func isOptIn(s SourceType) bool {
+	for _, optIn := range optInSourceTypes {
+		if s == optIn {
+			return true
+		}
+	}
+	return false
 }
 
 func TestIsEnabled_AllEnabled(t *testing.T) {
 	c := makeTestConfig(t, EnableAllSources())
 	for _, sourceType := range allSourceTypes {
 		t.Run(sourceType.String(), func(t *testing.T) {
+			if got, want := c.IsEnabled(sourceType), !isOptIn(sourceType); got != want {
+				t.Fatalf("IsEnabled(%s) = %v, want %v", sourceType, got, want)
+			}
+		})
+	}
+}
+
+func TestIsEnabled_OptIn(t *testing.T) {
+	for _, sourceType := range optInSourceTypes {
+		t.Run(sourceType.String(), func(t *testing.T) {
+			c := makeTestConfig(t)
+			if c.IsEnabled(sourceType) {
+				t.Fatalf("IsEnabled(%s) = true, want false", sourceType)
+			}
+			c = makeTestConfig(t, EnableAllSources(), EnableSource(sourceType))
 			if !c.IsEnabled(sourceType) {
 				t.Fatalf("IsEnabled(%s) = false, want true", sourceType)
 			}
@@ -93,3 +139,168 @@ func makeTestConfig(t *testing.T, opts ...Option) *config {
 	t.Helper()
 	return makeConfig(context.Background(), zaptest.NewLogger(t), opts...)
 }
+
+func TestGitLabHosts(t *testing.T) {
+	want := []string{"gitlab.com", "gitlab.example.com"}
+	c := makeTestConfig(t, GitLabHosts(want...))
+	if !reflect.DeepEqual(c.gitLabHosts, want) {
+		t.Fatalf("config.gitLabHosts = %v, want %v", c.gitLabHosts, want)
+	}
+}
+
+func TestGiteaHosts(t *testing.T) {
+	want := []string{"codeberg.org", "git.example.com"}
+	c := makeTestConfig(t, GiteaHosts(want...))
+	if !reflect.DeepEqual(c.giteaHosts, want) {
+		t.Fatalf("config.giteaHosts = %v, want %v", c.giteaHosts, want)
+	}
+}
+
+func TestGitCacheDir(t *testing.T) {
+	want := "/tmp/git-cache"
+	c := makeTestConfig(t, GitCacheDir(want))
+	if c.gitCacheDir != want {
+		t.Fatalf("config.gitCacheDir = %q, want %q", c.gitCacheDir, want)
+	}
+}
+
+func TestGitURLSchemes(t *testing.T) {
+	c := makeTestConfig(t)
+	if !reflect.DeepEqual(c.gitURLSchemes, gitclone.DefaultSchemes) {
+		t.Fatalf("config.gitURLSchemes = %v, want %v", c.gitURLSchemes, gitclone.DefaultSchemes)
+	}
+	want := []string{"https", "file"}
+	c = makeTestConfig(t, GitURLSchemes(want...))
+	if !reflect.DeepEqual(c.gitURLSchemes, want) {
+		t.Fatalf("config.gitURLSchemes = %v, want %v", c.gitURLSchemes, want)
+	}
+}
+
+func TestDepsDevBackend(t *testing.T) {
+	c := makeTestConfig(t, DepsDevBackend(DepsDevBackendAPI))
+	if c.depsDevBackend != DepsDevBackendAPI {
+		t.Fatalf("config.depsDevBackend = %v, want %v", c.depsDevBackend, DepsDevBackendAPI)
+	}
+}
+
+func TestDepsDevBackendTypeUnmarshalText(t *testing.T) {
+	//nolint:govet
+	tests := []struct {
+		text string
+		want DepsDevBackendType
+		err  error
+	}{
+		{text: "bigquery", want: DepsDevBackendBigQuery},
+		{text: "api", want: DepsDevBackendAPI},
+		{text: "unknown", err: ErrUnknownDepsDevBackend},
+	}
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			var got DepsDevBackendType
+			err := got.UnmarshalText([]byte(test.text))
+			if !errors.Is(err, test.err) {
+				t.Fatalf("UnmarshalText() = %v, want %v", err, test.err)
+			}
+			if got != test.want {
+				t.Fatalf("UnmarshalText() parsed %v, want %v", got, test.want)
+			}
+			if test.err == nil && got.String() != test.text {
+				t.Fatalf("String() = %q, want %q", got.String(), test.text)
+			}
+		})
+	}
+}
+
+func TestFailFast(t *testing.T) {
+	c := makeTestConfig(t)
+	if c.failFast {
+		t.Fatalf("config.failFast = %v, want %v", c.failFast, false)
+	}
+	c = makeTestConfig(t, FailFast())
+	if !c.failFast {
+		t.Fatalf("config.failFast = %v, want %v", c.failFast, true)
+	}
+}
+
+func TestSourceConcurrency(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want int
+	}{
+		{name: "default", want: DefaultSourceConcurrency},
+		{name: "set", opts: []Option{SourceConcurrency(8)}, want: 8},
+		{name: "sequential", opts: []Option{SourceConcurrency(1)}, want: 1},
+		{name: "invalid", opts: []Option{SourceConcurrency(0)}, want: DefaultSourceConcurrency},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := makeTestConfig(t, test.opts...)
+			if c.sourceConcurrency != test.want {
+				t.Fatalf("config.sourceConcurrency = %d, want %d", c.sourceConcurrency, test.want)
+			}
+		})
+	}
+}
+
+func TestTimeouts(t *testing.T) {
+	c := makeTestConfig(t)
+	if c.sourceTimeout != 0 {
+		t.Fatalf("config.sourceTimeout = %v, want %v", c.sourceTimeout, time.Duration(0))
+	}
+	if c.repoTimeout != 0 {
+		t.Fatalf("config.repoTimeout = %v, want %v", c.repoTimeout, time.Duration(0))
+	}
+	c = makeTestConfig(t, SourceTimeout(time.Minute), RepoTimeout(5*time.Minute))
+	if c.sourceTimeout != time.Minute {
+		t.Fatalf("config.sourceTimeout = %v, want %v", c.sourceTimeout, time.Minute)
+	}
+	if c.repoTimeout != 5*time.Minute {
+		t.Fatalf("config.repoTimeout = %v, want %v", c.repoTimeout, 5*time.Minute)
+	}
+}
+
+func TestRedirectForks(t *testing.T) {
+	c := makeTestConfig(t)
+	if c.redirectForks {
+		t.Fatalf("config.redirectForks = %v, want %v", c.redirectForks, false)
+	}
+	c = makeTestConfig(t, RedirectForks())
+	if !c.redirectForks {
+		t.Fatalf("config.redirectForks = %v, want %v", c.redirectForks, true)
+	}
+}
+
+func TestContributorsLookback(t *testing.T) {
+	want := 90 * 24 * time.Hour
+	c := makeTestConfig(t, ContributorsLookback(want))
+	if c.contribLookback != want {
+		t.Fatalf("config.contribLookback = %v, want %v", c.contribLookback, want)
+	}
+}
+
+func TestOrgDomainAliases(t *testing.T) {
+	want := emaildomain.Aliases{"chromium.org": "google.com"}
+	c := makeTestConfig(t, OrgDomainAliases(want))
+	if !reflect.DeepEqual(c.orgAliases, want) {
+		t.Fatalf("config.orgAliases = %v, want %v", c.orgAliases, want)
+	}
+}
+
+func TestOSVOptions(t *testing.T) {
+	c := makeTestConfig(t, OSVDataDir("/tmp/osv"), OSVLookback(24*time.Hour))
+	if c.osvDataDir != "/tmp/osv" {
+		t.Fatalf("config.osvDataDir = %q, want %q", c.osvDataDir, "/tmp/osv")
+	}
+	if c.osvLookback != 24*time.Hour {
+		t.Fatalf("config.osvLookback = %v, want %v", c.osvLookback, 24*time.Hour)
+	}
+}
+
+func TestScorecardResults(t *testing.T) {
+	want := "gs://bucket/results"
+	c := makeTestConfig(t, ScorecardResults(want))
+	if c.scorecardResults != want {
+		t.Fatalf("config.scorecardResults = %q, want %q", c.scorecardResults, want)
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package depsdev
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"go.uber.org/zap"
+
+	"github.com/ossf/criticality_score/v2/internal/collector/projectrepo"
+	"github.com/ossf/criticality_score/v2/internal/collector/signal"
+	"github.com/ossf/criticality_score/v2/internal/depsdevapi"
+)
+
+// maxAPIPackages is the default limit on the number of packages built from a
+// project whose dependents are counted using the deps.dev API. Up to two
+// requests are made for each package.
+const maxAPIPackages = 50
+
+// depsDevAPISource collects the same signals as depsDevSource, but uses the
+// deps.dev API rather than BigQuery.
+type depsDevAPISource struct {
+	logger      *zap.Logger
+	client      *depsdevapi.Client
+	maxPackages int
+}
+
+func (c *depsDevAPISource) EmptySet() signal.Set {
+	return &depsDevSet{}
+}
+
+func (c *depsDevAPISource) IsSupported(r projectrepo.Repo) bool {
+	_, ok := depsdevapi.ProjectID(r.URL())
+	return ok
+}
+
+func (c *depsDevAPISource) Get(ctx context.Context, r projectrepo.Repo, _ string) (signal.Set, error) {
+	var s depsDevSet
+	id, ok := depsdevapi.ProjectID(r.URL())
+	if !ok {
+		return &s, nil
+	}
+	c.logger.With(zap.String("url", r.URL().String())).Debug("Fetching deps.dev dependent count")
+	counts, found, err := c.dependentCounts(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch deps.dev dependent count: %w", err)
+	}
+	if found {
+		s.setCounts(counts)
+		s.PackagesTruncated.Set(counts.PackageCount > c.maxPackages)
+	}
+	return &s, nil
+}
+
+// dependentCounts returns the number of dependents of the latest version of
+// each package built from the project.
+//
+// This is consistent with the dependent counts calculated from the BigQuery
+// dataset, except that only the first c.maxPackages packages, ordered by system
+// and name, are counted. If the latest version of none of the packages counted
+// is built from the project found will be false.
+func (c *depsDevAPISource) dependentCounts(ctx context.Context, id string) (counts *dependentCounts, found bool, err error) {
+	versions, err := c.client.ProjectPackageVersions(ctx, id)
+	if errors.Is(err, depsdevapi.ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("project package versions: %w", err)
+	}
+
+	projectVersions := make(map[depsdevapi.VersionKey]bool)
+	seen := make(map[depsdevapi.PackageKey]bool)
+	var packages []depsdevapi.PackageKey
+	for _, v := range versions {
+		projectVersions[v] = true
+		p := depsdevapi.PackageKey{System: v.System, Name: v.Name}
+		if !seen[p] {
+			seen[p] = true
+			packages = append(packages, p)
+		}
+	}
+	sort.Slice(packages, func(i, j int) bool {
+		if packages[i].System != packages[j].System {
+			return packages[i].System < packages[j].System
+		}
+		return packages[i].Name < packages[j].Name
+	})
+
+	counts = &dependentCounts{PackageCount: len(packages)}
+	if len(packages) > c.maxPackages {
+		c.logger.Debug("Counting dependents of some packages only",
+			zap.Int("package_count", len(packages)),
+			zap.Int("max_packages", c.maxPackages))
+		packages = packages[:c.maxPackages]
+	}
+	for _, p := range packages {
+		pkg, err := c.client.Package(ctx, p)
+		if errors.Is(err, depsdevapi.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("package: %w", err)
+		}
+		latest, ok := pkg.DefaultVersion()
+		if !ok || !projectVersions[latest] {
+			// Only the latest version of each package is counted.
+			continue
+		}
+		d, err := c.client.Dependents(ctx, latest)
+		if errors.Is(err, depsdevapi.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("dependents: %w", err)
+		}
+		counts.add(latest.System, d.DependentCount)
+		found = true
+	}
+	return counts, found, nil
+}
+
+// NewAPISource creates a new Source for gathering data from deps.dev using
+// the deps.dev API.
+//
+// Unlike NewSource, no GCP project or credentials are required.
+func NewAPISource(logger *zap.Logger, client *depsdevapi.Client) signal.Source {
+	return &depsDevAPISource{
+		logger:      logger,
+		client:      client,
+		maxPackages: maxAPIPackages,
+	}
+}
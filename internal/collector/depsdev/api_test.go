@@ -0,0 +1,189 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package depsdev
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+
+	"github.com/ossf/criticality_score/v2/internal/depsdevapi"
+)
+
+type testRepo struct {
+	u *url.URL
+}
+
+func (r *testRepo) URL() *url.URL {
+	return r.u
+}
+
+func newTestRepo(t *testing.T, rawURL string) *testRepo {
+	t.Helper()
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatalf("url.Parse(%q) = %v", rawURL, err)
+	}
+	return &testRepo{u: u}
+}
+
+// serveFakeDepsDev is a minimal stand-in for the deps.dev API that knows
+// about a single project, "github.com/owner/repo".
+func serveFakeDepsDev(w http.ResponseWriter, r *http.Request) {
+	vk := func(system, name, version string) map[string]any {
+		return map[string]any{"system": system, "name": name, "version": version}
+	}
+	var v any
+	switch r.URL.EscapedPath() {
+	case "/v3alpha/projects/github.com%2Fowner%2Frepo:packageversions":
+		v = map[string]any{"versions": []any{
+			map[string]any{"versionKey": vk("NPM", "@owner/pkg", "2.0.0")},
+			map[string]any{"versionKey": vk("NPM", "@owner/pkg", "1.0.0")},
+			map[string]any{"versionKey": vk("PYPI", "pkg", "1.0.0")},
+			map[string]any{"versionKey": vk("GO", "github.com/owner/repo", "v1.0.0")},
+		}}
+	case "/v3alpha/systems/npm/packages/@owner%2Fpkg":
+		v = map[string]any{"versions": []any{
+			map[string]any{"versionKey": vk("NPM", "@owner/pkg", "1.0.0")},
+			map[string]any{"versionKey": vk("NPM", "@owner/pkg", "2.0.0"), "isDefault": true},
+		}}
+	case "/v3alpha/systems/pypi/packages/pkg":
+		// The latest version of the package is not built from the project.
+		v = map[string]any{"versions": []any{
+			map[string]any{"versionKey": vk("PYPI", "pkg", "1.0.0")},
+			map[string]any{"versionKey": vk("PYPI", "pkg", "1.1.0"), "isDefault": true},
+		}}
+	case "/v3alpha/systems/go/packages/github.com%2Fowner%2Frepo":
+		v = map[string]any{"versions": []any{
+			map[string]any{"versionKey": vk("GO", "github.com/owner/repo", "v1.0.0"), "isDefault": true},
+		}}
+	case "/v3alpha/systems/npm/packages/@owner%2Fpkg/versions/2.0.0:dependents":
+		v = map[string]any{"dependentCount": 30, "directDependentCount": 10, "indirectDependentCount": 20}
+	case "/v3alpha/systems/go/packages/github.com%2Fowner%2Frepo/versions/v1.0.0:dependents":
+		v = map[string]any{"dependentCount": 12, "directDependentCount": 12}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func newTestAPISource(t *testing.T) *depsDevAPISource {
+	t.Helper()
+	s := httptest.NewServer(http.HandlerFunc(serveFakeDepsDev))
+	t.Cleanup(s.Close)
+	client := depsdevapi.NewClient(s.Client(), s.URL+"/v3alpha")
+	return NewAPISource(zaptest.NewLogger(t), client).(*depsDevAPISource)
+}
+
+func TestAPISource(t *testing.T) {
+	src := newTestAPISource(t)
+	tests := []struct { //nolint:govet
+		name      string
+		url       string
+		supported bool
+		wantSet   bool
+		want      int
+	}{
+		{name: "found", url: "https://github.com/owner/repo", supported: true, wantSet: true, want: 42},
+		{name: "mixed case", url: "https://github.com/Owner/Repo", supported: true, wantSet: true, want: 42},
+		{name: "missing", url: "https://github.com/owner/missing", supported: true, wantSet: false},
+		{name: "unsupported host", url: "https://example.com/owner/repo", supported: false, wantSet: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := newTestRepo(t, test.url)
+			if got := src.IsSupported(r); got != test.supported {
+				t.Fatalf("IsSupported() = %v, want %v", got, test.supported)
+			}
+			set, err := src.Get(context.Background(), r, "")
+			if err != nil {
+				t.Fatalf("Get() = %v, want no error", err)
+			}
+			s := set.(*depsDevSet)
+			if got := s.DependentCount.IsSet(); got != test.wantSet {
+				t.Fatalf("DependentCount.IsSet() = %v, want %v", got, test.wantSet)
+			}
+			if got := s.DependentCount.Get(); got != test.want {
+				t.Errorf("DependentCount = %d, want %d", got, test.want)
+			}
+		})
+	}
+}
+
+func TestAPISource_Ecosystems(t *testing.T) {
+	src := newTestAPISource(t)
+	set, err := src.Get(context.Background(), newTestRepo(t, "https://github.com/owner/repo"), "")
+	if err != nil {
+		t.Fatalf("Get() = %v, want no error", err)
+	}
+	s := set.(*depsDevSet)
+	//nolint:govet
+	tests := []struct {
+		name string
+		got  int
+		want int
+	}{
+		{name: "NPMDependentCount", got: s.NPMDependentCount.Get(), want: 30},
+		{name: "PyPIDependentCount", got: s.PyPIDependentCount.Get(), want: 0},
+		{name: "GoDependentCount", got: s.GoDependentCount.Get(), want: 12},
+		{name: "MaxDependentCount", got: s.MaxDependentCount.Get(), want: 30},
+		{name: "PackageCount", got: s.PackageCount.Get(), want: 3},
+	}
+	for _, test := range tests {
+		if test.got != test.want {
+			t.Errorf("%s = %d, want %d", test.name, test.got, test.want)
+		}
+	}
+}
+
+func TestAPISource_MaxPackages(t *testing.T) {
+	//nolint:govet
+	tests := []struct {
+		name          string
+		maxPackages   int
+		want          int
+		wantTruncated bool
+	}{
+		{name: "all packages", maxPackages: maxAPIPackages, want: 42, wantTruncated: false},
+		{name: "truncated", maxPackages: 1, want: 12, wantTruncated: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			src := newTestAPISource(t)
+			src.maxPackages = test.maxPackages
+			set, err := src.Get(context.Background(), newTestRepo(t, "https://github.com/owner/repo"), "")
+			if err != nil {
+				t.Fatalf("Get() = %v, want no error", err)
+			}
+			s := set.(*depsDevSet)
+			if got := s.DependentCount.Get(); got != test.want {
+				t.Errorf("DependentCount = %d, want %d", got, test.want)
+			}
+			if got := s.PackageCount.Get(); got != 3 {
+				t.Errorf("PackageCount = %d, want 3", got)
+			}
+			if got := s.PackagesTruncated.Get(); !s.PackagesTruncated.IsSet() || got != test.wantTruncated {
+				t.Errorf("PackagesTruncated = %v, want %v", got, test.wantTruncated)
+			}
+		})
+	}
+}
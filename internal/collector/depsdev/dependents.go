@@ -28,13 +28,15 @@ import (
 )
 
 const (
-	dependentCountsTableName = "dependent_counts"
+	// dependentCountsTableName is the name of the table holding the dependent
+	// counts. It is versioned so that tables created by older releases with a
+	// different schema are not reused.
+	dependentCountsTableName = "dependent_counts_v2"
 
 	snapshotQuery = "SELECT MAX(Time) AS SnapshotTime FROM `bigquery-public-data.deps_dev_v1.Snapshots`"
 )
 
 // TODO: prune root dependents that come from the same project.
-// TODO: count "# packages per project" to determine dependent ratio
 
 const dataQuery = `
 CREATE TEMP TABLE rawDependentCounts(Name STRING, Version STRING, System STRING, DependentCount INT)
@@ -53,16 +55,38 @@ WITH pvp AS (
     SELECT System, Name, Version, ProjectName, ProjectType
     FROM ` + "`bigquery-public-data.deps_dev_v1.PackageVersionToProject`" + `
     WHERE SnapshotAt = @part
+),
+packageCounts AS (
+    SELECT ProjectName, ProjectType, COUNT(DISTINCT CONCAT(System, '/', Name)) AS PackageCount
+    FROM pvp
+    GROUP BY ProjectName, ProjectType
+),
+systemCounts AS (
+    SELECT pvp.ProjectName AS ProjectName, pvp.ProjectType AS ProjectType, pvp.System AS System, SUM(d.DependentCount) AS DependentCount
+    FROM pvp
+    JOIN rawDependentCounts AS d
+         ON (pvp.System = d.System AND pvp.Name = d.Name AND pvp.Version = d.Version)
+    GROUP BY ProjectName, ProjectType, System
 )
-SELECT pvp.ProjectName AS ProjectName, pvp.ProjectType AS ProjectType, SUM(d.DependentCount) AS DependentCount
- FROM pvp
- JOIN rawDependentCounts AS d
-      ON (pvp.System = d.System AND pvp.Name = d.Name AND pvp.Version = d.Version)
+SELECT ProjectName, ProjectType,
+       SUM(s.DependentCount) AS DependentCount,
+       SUM(IF(s.System = 'NPM', s.DependentCount, 0)) AS NPMDependentCount,
+       SUM(IF(s.System = 'PYPI', s.DependentCount, 0)) AS PyPIDependentCount,
+       SUM(IF(s.System = 'CARGO', s.DependentCount, 0)) AS CargoDependentCount,
+       SUM(IF(s.System = 'MAVEN', s.DependentCount, 0)) AS MavenDependentCount,
+       SUM(IF(s.System = 'GO', s.DependentCount, 0)) AS GoDependentCount,
+       SUM(IF(s.System = 'NUGET', s.DependentCount, 0)) AS NuGetDependentCount,
+       MAX(IF(s.System IN ('NPM', 'PYPI', 'CARGO', 'MAVEN', 'GO', 'NUGET'), s.DependentCount, 0)) AS MaxDependentCount,
+       ANY_VALUE(p.PackageCount) AS PackageCount
+ FROM systemCounts AS s
+ JOIN packageCounts AS p USING (ProjectName, ProjectType)
 GROUP BY ProjectName, ProjectType;
 `
 
 const countQuery = `
-SELECT DependentCount
+SELECT DependentCount, NPMDependentCount, PyPIDependentCount, CargoDependentCount,
+       MavenDependentCount, GoDependentCount, NuGetDependentCount, MaxDependentCount,
+       PackageCount
 FROM ` + "`{{.ProjectID}}.{{.DatasetName}}.{{.TableName}}`" + `
 WHERE ProjectName = @projectname AND ProjectType = @projecttype;
 `
@@ -114,27 +138,25 @@ func (c *dependents) generateQuery(queryTemplate, tableName string) string {
 	return b.String()
 }
 
-func (c *dependents) Count(ctx context.Context, projectName, projectType, tableKey string) (int, bool, error) {
+func (c *dependents) Count(ctx context.Context, projectName, projectType, tableKey string) (*dependentCounts, bool, error) {
 	query, err := c.prepareCountQuery(ctx, tableKey)
 	if err != nil {
-		return 0, false, fmt.Errorf("prepare count query: %w", err)
+		return nil, false, fmt.Errorf("prepare count query: %w", err)
 	}
 
-	var rec struct {
-		DependentCount int
-	}
+	var rec dependentCounts
 	params := map[string]any{
 		"projectname": projectName,
 		"projecttype": projectType,
 	}
 	err = c.b.OneResultQuery(ctx, query, params, &rec)
 	if err == nil {
-		return rec.DependentCount, true, nil
+		return &rec, true, nil
 	}
 	if errors.Is(err, ErrorNoResults) {
-		return 0, false, nil
+		return nil, false, nil
 	}
-	return 0, false, fmt.Errorf("count query: %w", err)
+	return nil, false, fmt.Errorf("count query: %w", err)
 }
 
 func (c *dependents) getLatestSnapshotTime(ctx context.Context) (time.Time, error) {
@@ -32,8 +32,78 @@ const (
 	defaultLocation = "US"
 )
 
+//nolint:govet
 type depsDevSet struct {
-	DependentCount signal.Field[int] `signal:"dependent_count"`
+	DependentCount signal.Field[int] `signal:"dependent_count" desc:"Number of packages that depend on packages built from the repository." unit:"count" source:"deps.dev"`
+
+	NPMDependentCount   signal.Field[int] `signal:"npm_dependent_count" desc:"Number of npm packages that depend on packages built from the repository." unit:"count" source:"deps.dev"`
+	PyPIDependentCount  signal.Field[int] `signal:"pypi_dependent_count" desc:"Number of PyPI packages that depend on packages built from the repository." unit:"count" source:"deps.dev"`
+	CargoDependentCount signal.Field[int] `signal:"cargo_dependent_count" desc:"Number of Cargo packages that depend on packages built from the repository." unit:"count" source:"deps.dev"`
+	MavenDependentCount signal.Field[int] `signal:"maven_dependent_count" desc:"Number of Maven packages that depend on packages built from the repository." unit:"count" source:"deps.dev"`
+	GoDependentCount    signal.Field[int] `signal:"go_dependent_count" desc:"Number of Go modules that depend on packages built from the repository." unit:"count" source:"deps.dev"`
+	NuGetDependentCount signal.Field[int] `signal:"nuget_dependent_count" desc:"Number of NuGet packages that depend on packages built from the repository." unit:"count" source:"deps.dev"`
+
+	// MaxDependentCount is the largest dependent count of any one ecosystem.
+	MaxDependentCount signal.Field[int] `signal:"max_dependent_count" desc:"Largest dependent count of any one ecosystem." unit:"count" source:"deps.dev"`
+
+	PackageCount signal.Field[int] `signal:"package_count" desc:"Number of packages built from the repository." unit:"count" source:"deps.dev"`
+
+	// PackagesTruncated is true if only some of the packages built from the
+	// repository were included in the dependent counts. It is only set when
+	// the deps.dev API is used.
+	PackagesTruncated signal.Field[bool] `desc:"Whether only some of the packages built from the repository were included in the dependent counts." source:"deps.dev"`
+}
+
+// dependentCounts holds the dependent counts for a single project.
+//
+// The field names match the columns in the BigQuery dependent counts table.
+type dependentCounts struct {
+	DependentCount      int
+	NPMDependentCount   int
+	PyPIDependentCount  int
+	CargoDependentCount int
+	MavenDependentCount int
+	GoDependentCount    int
+	NuGetDependentCount int
+	MaxDependentCount   int
+	PackageCount        int
+}
+
+// add includes n dependents of a package in the given system in the counts.
+func (c *dependentCounts) add(system string, n int) {
+	c.DependentCount += n
+	var count *int
+	switch system {
+	case "NPM":
+		count = &c.NPMDependentCount
+	case "PYPI":
+		count = &c.PyPIDependentCount
+	case "CARGO":
+		count = &c.CargoDependentCount
+	case "MAVEN":
+		count = &c.MavenDependentCount
+	case "GO":
+		count = &c.GoDependentCount
+	case "NUGET":
+		count = &c.NuGetDependentCount
+	default:
+		return
+	}
+	*count += n
+	c.MaxDependentCount = max(c.MaxDependentCount, *count)
+}
+
+// setCounts sets all the dependent count fields from c.
+func (s *depsDevSet) setCounts(c *dependentCounts) {
+	s.DependentCount.Set(c.DependentCount)
+	s.NPMDependentCount.Set(c.NPMDependentCount)
+	s.PyPIDependentCount.Set(c.PyPIDependentCount)
+	s.CargoDependentCount.Set(c.CargoDependentCount)
+	s.MavenDependentCount.Set(c.MavenDependentCount)
+	s.GoDependentCount.Set(c.GoDependentCount)
+	s.NuGetDependentCount.Set(c.NuGetDependentCount)
+	s.MaxDependentCount.Set(c.MaxDependentCount)
+	s.PackageCount.Set(c.PackageCount)
 }
 
 func (s *depsDevSet) Namespace() signal.Namespace {
@@ -61,12 +131,12 @@ func (c *depsDevSource) Get(ctx context.Context, r projectrepo.Repo, jobID strin
 		return &s, nil
 	}
 	c.logger.With(zap.String("url", r.URL().String())).Debug("Fetching deps.dev dependent count")
-	deps, found, err := c.dependents.Count(ctx, n, t, jobID)
+	counts, found, err := c.dependents.Count(ctx, n, t, jobID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch deps.dev dependent count: %w", err)
 	}
 	if found {
-		s.DependentCount.Set(deps)
+		s.setCounts(counts)
 	}
 	return &s, nil
 }
@@ -102,6 +172,8 @@ func parseRepoURL(u *url.URL) (projectName, projectType string) {
 	switch hn := u.Hostname(); hn {
 	case "github.com":
 		return strings.Trim(u.Path, "/"), "GITHUB"
+	case "gitlab.com":
+		return strings.Trim(u.Path, "/"), "GITLAB"
 	default:
 		return "", ""
 	}
@@ -0,0 +1,229 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package downloads
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/module"
+
+	"github.com/ossf/criticality_score/v2/internal/retry"
+)
+
+const (
+	defaultNPMURL     = "https://api.npmjs.org"
+	defaultPyPIURL    = "https://pypistats.org"
+	defaultCratesURL  = "https://crates.io"
+	defaultGoProxyURL = "https://proxy.golang.org"
+
+	// userAgent identifies requests to the registries, as required by the
+	// crates.io crawler policy.
+	userAgent = "criticality_score (https://github.com/ossf/criticality_score)"
+)
+
+// errNotFound is returned when a registry responds with a 404.
+var errNotFound = errors.New("not found")
+
+// endpoints contains the base URLs of the registry APIs.
+type endpoints struct {
+	npm     string
+	pypi    string
+	crates  string
+	goProxy string
+}
+
+var defaultEndpoints = endpoints{
+	npm:     defaultNPMURL,
+	pypi:    defaultPyPIURL,
+	crates:  defaultCratesURL,
+	goProxy: defaultGoProxyURL,
+}
+
+// registries fetches download counts from package registries.
+type registries struct {
+	http      *http.Client
+	endpoints endpoints
+}
+
+// npmLast30d returns the number of downloads of the npm package over the last
+// 30 days.
+func (r *registries) npmLast30d(ctx context.Context, name string) (int, error) {
+	var resp struct {
+		Downloads int `json:"downloads"`
+	}
+	// Scoped packages, such as "@scope/name", keep the slash unescaped.
+	path := "downloads/point/last-month/" + escapePackageName(name)
+	if err := r.get(ctx, r.endpoints.npm, path, &resp); err != nil {
+		return 0, err
+	}
+	return resp.Downloads, nil
+}
+
+// pypiLast30d returns the number of downloads of the PyPI package over the
+// last month.
+func (r *registries) pypiLast30d(ctx context.Context, name string) (int, error) {
+	var resp struct {
+		Data struct {
+			LastMonth int `json:"last_month"`
+		} `json:"data"`
+	}
+	path := "api/packages/" + url.PathEscape(strings.ToLower(name)) + "/recent"
+	if err := r.get(ctx, r.endpoints.pypi, path, &resp); err != nil {
+		return 0, err
+	}
+	return resp.Data.LastMonth, nil
+}
+
+// cratesLast30d returns the number of downloads of the crate over the 30 days
+// before now.
+//
+// crates.io only reports daily downloads for the last 90 days, split between
+// the most recent versions and all the other versions.
+func (r *registries) cratesLast30d(ctx context.Context, name string, now time.Time) (int, error) {
+	type daily struct {
+		Date      string `json:"date"`
+		Downloads int    `json:"downloads"`
+	}
+	var resp struct {
+		VersionDownloads []daily `json:"version_downloads"`
+		Meta             struct {
+			ExtraDownloads []daily `json:"extra_downloads"`
+		} `json:"meta"`
+	}
+	path := "api/v1/crates/" + url.PathEscape(name) + "/downloads"
+	if err := r.get(ctx, r.endpoints.crates, path, &resp); err != nil {
+		return 0, err
+	}
+	cutoff := now.UTC().AddDate(0, 0, -30).Format(time.DateOnly)
+	total := 0
+	for _, d := range append(resp.VersionDownloads, resp.Meta.ExtraDownloads...) {
+		// Dates are formatted as YYYY-MM-DD so they can be compared as
+		// strings.
+		if d.Date > cutoff {
+			total += d.Downloads
+		}
+	}
+	return total, nil
+}
+
+// goVersionCount returns the number of tagged versions of the Go module at
+// path that are available from the Go module proxy.
+//
+// The Go module proxy does not publish download counts, so this is the closest
+// measure of use it provides.
+func (r *registries) goVersionCount(ctx context.Context, path string) (int, error) {
+	escaped, err := module.EscapePath(path)
+	if err != nil {
+		return 0, fmt.Errorf("escape module path: %w", err)
+	}
+	body, err := r.fetch(ctx, r.endpoints.goProxy, escaped+"/@v/list", "text/plain")
+	if err != nil {
+		return 0, err
+	}
+	// The list holds one version per line, and is empty if the module has no
+	// tagged versions.
+	return len(strings.Fields(string(body))), nil
+}
+
+// get requests path from the API at baseURL and parses the JSON response into
+// v.
+//
+// The path must already be escaped.
+func (r *registries) get(ctx context.Context, baseURL, path string, v any) error {
+	body, err := r.fetch(ctx, baseURL, path, "application/json")
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("reading response for %s/%s: %w", baseURL, path, err)
+	}
+	return nil
+}
+
+// fetch requests path from the API at baseURL, accepting the given media type,
+// and returns the body of the response. An error wrapping errNotFound is
+// returned if the path does not exist.
+//
+// The path must already be escaped.
+func (r *registries) fetch(ctx context.Context, baseURL, path, accept string) ([]byte, error) {
+	u := strings.TrimSuffix(baseURL, "/") + "/" + path
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("new request: %w", err)
+	}
+	req.Header.Set("Accept", accept)
+	resp, err := r.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("registry request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		// The Go module proxy uses 410 Gone for modules it cannot serve.
+		return nil, fmt.Errorf("%w: %s", errNotFound, u)
+	case resp.StatusCode < 200 || 300 <= resp.StatusCode:
+		return nil, fmt.Errorf("registry request %s: unexpected status %s", u, resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response for %s: %w", u, err)
+	}
+	return body, nil
+}
+
+// escapePackageName escapes each segment of a package name that may contain
+// a slash, such as an npm scoped package.
+func escapePackageName(name string) string {
+	parts := strings.Split(name, "/")
+	for i, p := range parts {
+		parts[i] = url.PathEscape(p)
+	}
+	return strings.Join(parts, "/")
+}
+
+// NewTransport returns an http.RoundTripper for communicating with the
+// package registries.
+//
+// Requests identify themselves with a User-Agent, and are retried if they are
+// rate limited or fail with a server error.
+func NewTransport(inner http.RoundTripper) http.RoundTripper {
+	return retry.NewRoundTripper(&userAgentRoundTripper{inner: inner},
+		retry.InitialDelay(time.Minute),
+		retry.RetryAfter(retry.RetryAfterSeconds),
+		retry.Strategy(retry.TooManyRequests),
+		retry.Strategy(retry.ServerError),
+	)
+}
+
+// userAgentRoundTripper sets the User-Agent header of each request.
+type userAgentRoundTripper struct {
+	inner http.RoundTripper
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (rt *userAgentRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.Header.Set("User-Agent", userAgent)
+	return rt.inner.RoundTrip(r)
+}
@@ -0,0 +1,166 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package downloads provides a signal Source for the number of times the
+// packages built from a repository have been downloaded from their registries.
+//
+// The packages built from a repository are found using deps.dev, so only
+// repositories known to deps.dev are supported. Download counts are collected
+// for packages published to npm, PyPI and crates.io.
+//
+// The Go module proxy does not publish download counts, so for Go modules the
+// number of modules and tagged versions served by the proxy are collected
+// instead. These are not included in the total.
+package downloads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ossf/criticality_score/v2/internal/collector/projectrepo"
+	"github.com/ossf/criticality_score/v2/internal/collector/signal"
+	"github.com/ossf/criticality_score/v2/internal/depsdevapi"
+)
+
+//nolint:govet
+type downloadsSet struct {
+	Last30dTotal signal.Field[int] `signal:"last_30d_total" desc:"Number of downloads of packages built from the repository across all registries." unit:"downloads" lookback:"30 days" source:"npm, PyPI and crates.io"`
+
+	NPMLast30d   signal.Field[int] `signal:"npm_last_30d" desc:"Number of downloads of npm packages built from the repository." unit:"downloads" lookback:"30 days" source:"npm"`
+	PyPILast30d  signal.Field[int] `signal:"pypi_last_30d" desc:"Number of downloads of PyPI packages built from the repository." unit:"downloads" lookback:"30 days" source:"PyPI"`
+	CargoLast30d signal.Field[int] `signal:"cargo_last_30d" desc:"Number of downloads of crates built from the repository." unit:"downloads" lookback:"30 days" source:"crates.io"`
+
+	GoProxyModuleCount  signal.Field[int] `signal:"go_proxy_module_count" desc:"Number of Go modules built from the repository that are served by the Go module proxy." unit:"count" source:"Go module proxy"`
+	GoProxyVersionCount signal.Field[int] `signal:"go_proxy_version_count" desc:"Number of tagged versions of Go modules built from the repository that are served by the Go module proxy." unit:"count" source:"Go module proxy"`
+}
+
+func (s *downloadsSet) Namespace() signal.Namespace {
+	return signal.Namespace("downloads")
+}
+
+type Source struct {
+	logger     *zap.Logger
+	depsDev    *depsdevapi.Client
+	registries *registries
+}
+
+// NewSource creates a new Source that uses client to fetch download counts
+// from the public package registries.
+func NewSource(logger *zap.Logger, depsDev *depsdevapi.Client, client *http.Client) signal.Source {
+	return &Source{
+		logger:  logger,
+		depsDev: depsDev,
+		registries: &registries{
+			http:      client,
+			endpoints: defaultEndpoints,
+		},
+	}
+}
+
+func (c *Source) EmptySet() signal.Set {
+	return &downloadsSet{}
+}
+
+func (c *Source) IsSupported(r projectrepo.Repo) bool {
+	_, ok := depsdevapi.ProjectID(r.URL())
+	return ok
+}
+
+func (c *Source) Get(ctx context.Context, r projectrepo.Repo, _ string) (signal.Set, error) {
+	s := &downloadsSet{}
+	id, ok := depsdevapi.ProjectID(r.URL())
+	if !ok {
+		return s, nil
+	}
+	logger := c.logger.With(zap.String("url", r.URL().String()))
+
+	logger.Debug("Fetching packages from deps.dev")
+	versions, err := c.depsDev.ProjectPackageVersions(ctx, id)
+	if errors.Is(err, depsdevapi.ErrNotFound) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fetch packages: %w", err)
+	}
+
+	logger.Debug("Fetching downloads")
+	now := time.Now()
+	seen := make(map[depsdevapi.PackageKey]bool)
+	npm, pypi, cargo := 0, 0, 0
+	goModules, goVersions := 0, 0
+	found, foundGo := false, false
+	for _, v := range versions {
+		p := depsdevapi.PackageKey{System: v.System, Name: v.Name}
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+
+		var n int
+		var count *int
+		switch p.System {
+		case "NPM":
+			n, err = c.registries.npmLast30d(ctx, p.Name)
+			count = &npm
+		case "PYPI":
+			n, err = c.registries.pypiLast30d(ctx, p.Name)
+			count = &pypi
+		case "CARGO":
+			n, err = c.registries.cratesLast30d(ctx, p.Name, now)
+			count = &cargo
+		case "GO":
+			foundGo = true
+			n, err = c.registries.goVersionCount(ctx, p.Name)
+			if errors.Is(err, errNotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("fetch GO versions: %w", err)
+			}
+			goModules++
+			goVersions += n
+			continue
+		default:
+			continue
+		}
+		found = true
+		if errors.Is(err, errNotFound) {
+			// The package may have been removed from the registry.
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("fetch %s downloads: %w", p.System, err)
+		}
+		*count += n
+	}
+	if foundGo {
+		s.GoProxyModuleCount.Set(goModules)
+		s.GoProxyVersionCount.Set(goVersions)
+	}
+	if !found {
+		// None of the packages are published to a registry with download
+		// counts.
+		return s, nil
+	}
+	s.NPMLast30d.Set(npm)
+	s.PyPILast30d.Set(pypi)
+	s.CargoLast30d.Set(cargo)
+	s.Last30dTotal.Set(npm + pypi + cargo)
+	return s, nil
+}
@@ -0,0 +1,176 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package downloads
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+
+	"github.com/ossf/criticality_score/v2/internal/depsdevapi"
+)
+
+type testRepo struct {
+	u *url.URL
+}
+
+func (r *testRepo) URL() *url.URL {
+	return r.u
+}
+
+// serveFake is a stand-in for deps.dev and each of the package registries.
+func serveFake(w http.ResponseWriter, r *http.Request) {
+	vk := func(system, name string) map[string]any {
+		return map[string]any{"versionKey": map[string]any{"system": system, "name": name, "version": "1.0.0"}}
+	}
+	day := func(daysAgo, downloads int) map[string]any {
+		return map[string]any{
+			"date":      time.Now().UTC().AddDate(0, 0, -daysAgo).Format(time.DateOnly),
+			"downloads": downloads,
+		}
+	}
+	var v any
+	switch r.URL.EscapedPath() {
+	case "/depsdev/projects/github.com%2Fowner%2Frepo:packageversions":
+		v = map[string]any{"versions": []any{
+			vk("NPM", "@owner/pkg"),
+			vk("NPM", "@owner/pkg"),
+			vk("NPM", "removed"),
+			vk("PYPI", "Pkg"),
+			vk("CARGO", "pkg"),
+			vk("GO", "github.com/owner/repo"),
+			vk("GO", "github.com/owner/repo"),
+			vk("GO", "github.com/owner/Mixed/Case"),
+			vk("GO", "github.com/owner/retracted"),
+		}}
+	case "/depsdev/projects/github.com%2Fowner%2Fgo-only:packageversions":
+		v = map[string]any{"versions": []any{vk("GO", "github.com/owner/go-only")}}
+	case "/npm/downloads/point/last-month/@owner/pkg":
+		v = map[string]any{"downloads": 1000, "package": "@owner/pkg"}
+	case "/pypi/api/packages/pkg/recent":
+		v = map[string]any{"data": map[string]any{"last_day": 1, "last_week": 7, "last_month": 30}}
+	case "/goproxy/github.com/owner/repo/@v/list":
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("v1.0.0\nv1.1.0\n"))
+		return
+	case "/goproxy/github.com/owner/go-only/@v/list":
+		// Modules with only pseudo-versions have an empty list.
+		w.Header().Set("Content-Type", "text/plain")
+		return
+	case "/goproxy/github.com/owner/!mixed/!case/@v/list":
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("v0.1.0\n"))
+		return
+	case "/crates/api/v1/crates/pkg/downloads":
+		v = map[string]any{
+			"version_downloads": []any{day(1, 5), day(10, 5), day(60, 100)},
+			"meta":              map[string]any{"extra_downloads": []any{day(2, 3), day(45, 100)}},
+		}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func newTestSource(t *testing.T) *Source {
+	t.Helper()
+	s := httptest.NewServer(http.HandlerFunc(serveFake))
+	t.Cleanup(s.Close)
+	src := NewSource(zaptest.NewLogger(t), depsdevapi.NewClient(s.Client(), s.URL+"/depsdev"), s.Client()).(*Source)
+	src.registries.endpoints = endpoints{
+		npm:     s.URL + "/npm",
+		pypi:    s.URL + "/pypi",
+		crates:  s.URL + "/crates",
+		goProxy: s.URL + "/goproxy",
+	}
+	return src
+}
+
+func TestSource(t *testing.T) {
+	src := newTestSource(t)
+	u, _ := url.Parse("https://github.com/owner/repo")
+	set, err := src.Get(context.Background(), &testRepo{u: u}, "")
+	if err != nil {
+		t.Fatalf("Get() = %v, want no error", err)
+	}
+	s := set.(*downloadsSet)
+	//nolint:govet
+	tests := []struct {
+		name string
+		got  int
+		want int
+	}{
+		{name: "NPMLast30d", got: s.NPMLast30d.Get(), want: 1000},
+		{name: "PyPILast30d", got: s.PyPILast30d.Get(), want: 30},
+		{name: "CargoLast30d", got: s.CargoLast30d.Get(), want: 13},
+		{name: "Last30dTotal", got: s.Last30dTotal.Get(), want: 1043},
+		{name: "GoProxyModuleCount", got: s.GoProxyModuleCount.Get(), want: 2},
+		{name: "GoProxyVersionCount", got: s.GoProxyVersionCount.Get(), want: 3},
+	}
+	for _, test := range tests {
+		if test.got != test.want {
+			t.Errorf("%s = %d, want %d", test.name, test.got, test.want)
+		}
+	}
+}
+
+func TestSource_GoOnly(t *testing.T) {
+	src := newTestSource(t)
+	u, _ := url.Parse("https://github.com/owner/go-only")
+	set, err := src.Get(context.Background(), &testRepo{u: u}, "")
+	if err != nil {
+		t.Fatalf("Get() = %v, want no error", err)
+	}
+	s := set.(*downloadsSet)
+	if s.Last30dTotal.IsSet() {
+		t.Errorf("Last30dTotal is set, want unset")
+	}
+	if got, want := s.GoProxyModuleCount.Get(), 1; got != want {
+		t.Errorf("GoProxyModuleCount = %d, want %d", got, want)
+	}
+	if got, want := s.GoProxyVersionCount.Get(), 0; got != want {
+		t.Errorf("GoProxyVersionCount = %d, want %d", got, want)
+	}
+}
+
+func TestSource_Unset(t *testing.T) {
+	src := newTestSource(t)
+	for _, rawURL := range []string{
+		"https://github.com/owner/missing",
+	} {
+		t.Run(rawURL, func(t *testing.T) {
+			u, _ := url.Parse(rawURL)
+			set, err := src.Get(context.Background(), &testRepo{u: u}, "")
+			if err != nil {
+				t.Fatalf("Get() = %v, want no error", err)
+			}
+			s := set.(*downloadsSet)
+			if s.Last30dTotal.IsSet() {
+				t.Errorf("Last30dTotal is set, want unset")
+			}
+			if s.GoProxyModuleCount.IsSet() {
+				t.Errorf("GoProxyModuleCount is set, want unset")
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package emaildomain provides helpers for attributing commit author email
+// addresses to the organizations that own their domain.
+package emaildomain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// webmailDomains are email domains used by individuals rather than
+// organizations, and are ignored when counting organizations.
+var webmailDomains = map[string]bool{
+	"163.com":                  true,
+	"gmail.com":                true,
+	"gmx.de":                   true,
+	"gmx.net":                  true,
+	"googlemail.com":           true,
+	"hotmail.com":              true,
+	"icloud.com":               true,
+	"live.com":                 true,
+	"mail.ru":                  true,
+	"me.com":                   true,
+	"outlook.com":              true,
+	"proton.me":                true,
+	"protonmail.com":           true,
+	"qq.com":                   true,
+	"users.noreply.github.com": true,
+	"yahoo.com":                true,
+	"yandex.ru":                true,
+}
+
+// Domain returns the lowercase domain of the email address, or an empty
+// string if it does not have one.
+func Domain(email string) string {
+	i := strings.LastIndex(email, "@")
+	if i < 0 {
+		return ""
+	}
+	domain := strings.ToLower(email[i+1:])
+	if !strings.Contains(domain, ".") {
+		// Ignore local or placeholder domains such as "localhost".
+		return ""
+	}
+	return domain
+}
+
+// IsWebmail returns true if the domain belongs to a free webmail provider.
+func IsWebmail(domain string) bool {
+	return webmailDomains[domain]
+}
+
+// Aliases maps an email domain to the domain of the organization that owns it,
+// for organizations that use more than one domain.
+type Aliases map[string]string
+
+// ParseAliases parses a comma separated list of "from=to" domain pairs.
+func ParseAliases(s string) (Aliases, error) {
+	a := make(Aliases)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		from, to, ok := strings.Cut(pair, "=")
+		from = strings.ToLower(strings.TrimSpace(from))
+		to = strings.ToLower(strings.TrimSpace(to))
+		if !ok || from == "" || to == "" {
+			return nil, fmt.Errorf("invalid domain alias %q", pair)
+		}
+		a[from] = to
+	}
+	return a, nil
+}
+
+// Org returns the domain of the organization for the email address, after
+// applying the aliases. An empty string is returned if the address does not
+// have a domain, or if it belongs to a webmail provider.
+func (a Aliases) Org(email string) string {
+	domain := Domain(email)
+	if to, ok := a[domain]; ok {
+		domain = to
+	}
+	if IsWebmail(domain) {
+		return ""
+	}
+	return domain
+}
@@ -0,0 +1,82 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emaildomain
+
+import (
+	"testing"
+)
+
+func TestDomain(t *testing.T) {
+	tests := []struct {
+		email string
+		want  string
+	}{
+		{email: "alice@example.com", want: "example.com"},
+		{email: "Alice@Example.COM", want: "example.com"},
+		{email: "alice@localhost", want: ""},
+		{email: "alice", want: ""},
+		{email: "", want: ""},
+	}
+	for _, test := range tests {
+		t.Run(test.email, func(t *testing.T) {
+			if got := Domain(test.email); got != test.want {
+				t.Errorf("Domain(%q) = %q, want %q", test.email, got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseAliases(t *testing.T) {
+	a, err := ParseAliases("googlemail.com=gmail.com, Chromium.org = google.com,")
+	if err != nil {
+		t.Fatalf("ParseAliases() = %v, want no error", err)
+	}
+	want := Aliases{"googlemail.com": "gmail.com", "chromium.org": "google.com"}
+	if len(a) != len(want) {
+		t.Fatalf("ParseAliases() = %v, want %v", a, want)
+	}
+	for from, to := range want {
+		if got := a[from]; got != to {
+			t.Errorf("alias %q = %q, want %q", from, got, to)
+		}
+	}
+
+	for _, s := range []string{"example.com", "=example.com", "example.com="} {
+		if _, err := ParseAliases(s); err == nil {
+			t.Errorf("ParseAliases(%q) = no error, want error", s)
+		}
+	}
+}
+
+func TestAliasesOrg(t *testing.T) {
+	a := Aliases{"chromium.org": "google.com", "example.net": "gmail.com"}
+	tests := []struct {
+		email string
+		want  string
+	}{
+		{email: "alice@chromium.org", want: "google.com"},
+		{email: "bob@google.com", want: "google.com"},
+		{email: "carol@example.net", want: ""},
+		{email: "dave@gmail.com", want: ""},
+		{email: "erin@localhost", want: ""},
+	}
+	for _, test := range tests {
+		t.Run(test.email, func(t *testing.T) {
+			if got := a.Org(test.email); got != test.want {
+				t.Errorf("Org(%q) = %q, want %q", test.email, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitclone
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/ossf/criticality_score/v2/internal/collector/projectrepo"
+)
+
+// notFoundMessages are fragments of git's error output that indicate the
+// repository does not exist.
+var notFoundMessages = []string{
+	"not found",
+	"does not exist",
+	"does not appear to be a git repository",
+}
+
+// DefaultSchemes is the default set of URL schemes for repositories that are
+// cloned.
+//
+// The "ssh" and "file" schemes are not included as they allow the URLs being
+// collected to reach SSH credentials and local paths.
+var DefaultSchemes = []string{"https", "http", "git"}
+
+type factory struct {
+	logger   *zap.Logger
+	cacheDir string
+	schemes  []string
+}
+
+// NewRepoFactory returns a projectrepo.Factory that matches any repository
+// URL that git can clone using one of the given schemes.
+//
+// Repositories are cloned into cacheDir, and are updated rather than cloned
+// again if they are already present.
+//
+// As it matches any URL, this factory must be registered after all the other
+// factories.
+func NewRepoFactory(logger *zap.Logger, cacheDir string, schemes []string) projectrepo.Factory {
+	return &factory{
+		logger:   logger,
+		cacheDir: cacheDir,
+		schemes:  schemes,
+	}
+}
+
+func (f *factory) New(ctx context.Context, u *url.URL) (projectrepo.Repo, error) {
+	r := &repo{
+		url:    u,
+		dir:    filepath.Join(f.cacheDir, cacheKey(u)),
+		logger: f.logger.With(zap.String("url", u.String())),
+	}
+	if err := r.init(ctx); err != nil {
+		var gitErr *gitError
+		if errors.As(err, &gitErr) && isNotFound(gitErr) {
+			return nil, fmt.Errorf("%w (%s): %w", projectrepo.ErrNoRepoFound, u, err)
+		}
+		if errors.As(err, &gitErr) {
+			return nil, fmt.Errorf("%w (%s): %w", projectrepo.ErrRepoInaccessible, u, err)
+		}
+		return nil, err
+	}
+	return r, nil
+}
+
+func (f *factory) Match(u *url.URL) bool {
+	if !slices.Contains(f.schemes, u.Scheme) {
+		return false
+	}
+	switch u.Scheme {
+	case "http", "https", "git", "ssh":
+		return u.Host != "" && strings.Trim(u.Path, "/") != ""
+	case "file":
+		return u.Path != ""
+	default:
+		return false
+	}
+}
+
+// cacheKey returns the name of the directory inside the cache used to store
+// the clone of the repository at u.
+func cacheKey(u *url.URL) string {
+	h := sha256.Sum256([]byte(u.String()))
+	return hex.EncodeToString(h[:16]) + ".git"
+}
+
+func isNotFound(err *gitError) bool {
+	stderr := strings.ToLower(err.stderr)
+	for _, m := range notFoundMessages {
+		if strings.Contains(stderr, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// ensureCacheDir creates the cache directory if it does not exist.
+func ensureCacheDir(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create cache dir: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitclone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"go.uber.org/zap"
+)
+
+// repo implements the projectrepo.Repo interface for a repository cloned
+// into a local directory.
+type repo struct {
+	url    *url.URL
+	dir    string
+	logger *zap.Logger
+}
+
+// URL implements the projectrepo.Repo interface.
+func (r *repo) URL() *url.URL {
+	return r.url
+}
+
+// init ensures an up-to-date bare clone of the repository is present in dir.
+//
+// Clones are partial, omitting all blobs, as only the commit history and tags
+// are needed to collect signals.
+func (r *repo) init(ctx context.Context) error {
+	_, err := os.Stat(r.dir)
+	switch {
+	case err == nil:
+		r.logger.Debug("Fetching updates for cached clone", zap.String("dir", r.dir))
+		_, err := runGit(ctx, r.dir, "fetch", "--quiet", "--prune", "--force", "origin",
+			"+refs/heads/*:refs/heads/*", "+refs/tags/*:refs/tags/*")
+		return err
+	case !errors.Is(err, fs.ErrNotExist):
+		return fmt.Errorf("stat clone dir: %w", err)
+	}
+
+	parent := filepath.Dir(r.dir)
+	if err := ensureCacheDir(parent); err != nil {
+		return err
+	}
+	// Clone into a temporary directory first so an interrupted clone is never
+	// mistaken for a complete one.
+	tmp, err := os.MkdirTemp(parent, "clone-*")
+	if err != nil {
+		return fmt.Errorf("create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	r.logger.Debug("Cloning repository", zap.String("dir", r.dir))
+	if _, err := runGit(ctx, parent, "clone", "--quiet", "--bare", "--filter=blob:none", "--", r.url.String(), tmp); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, r.dir); err != nil {
+		return fmt.Errorf("move clone into cache: %w", err)
+	}
+	return nil
+}
+
+// hasCommits returns true if the repository's HEAD points to a commit.
+func (r *repo) hasCommits(ctx context.Context) bool {
+	_, err := runGit(ctx, r.dir, "rev-parse", "--verify", "--quiet", "HEAD^{commit}")
+	return err == nil
+}
@@ -0,0 +1,183 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gitclone provides a projectrepo.Factory and signal Source for any
+// repository that can be cloned with git.
+//
+// It is intended as a fallback for repositories that are not hosted on a
+// forge with a dedicated source, so only the signals that can be derived from
+// the commit history and tags are collected.
+package gitclone
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ossf/criticality_score/v2/internal/collector/emaildomain"
+	"github.com/ossf/criticality_score/v2/internal/collector/github/legacy"
+	"github.com/ossf/criticality_score/v2/internal/collector/projectrepo"
+	"github.com/ossf/criticality_score/v2/internal/collector/signal"
+)
+
+const (
+	legacyReleaseLookback = 365 * 24 * time.Hour
+	legacyCommitLookback  = 365 * 24 * time.Hour
+)
+
+// commit contains the fields of a commit used for collecting signals.
+type commit struct {
+	committed time.Time
+	email     string
+}
+
+type Source struct{}
+
+func (s *Source) EmptySet() signal.Set {
+	return &signal.RepoSet{}
+}
+
+func (s *Source) Get(ctx context.Context, r projectrepo.Repo, _ string) (signal.Set, error) {
+	gr, ok := r.(*repo)
+	if !ok {
+		return nil, errors.New("project is not a cloned git repository")
+	}
+	now := time.Now()
+
+	set := &signal.RepoSet{
+		URL: signal.Val(r.URL().String()),
+	}
+	if !gr.hasCommits(ctx) {
+		gr.logger.Debug("Repository has no commits")
+		return set, nil
+	}
+
+	gr.logger.Debug("Reading commit history")
+	commits, err := gr.commits(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("read commits: %w", err)
+	}
+
+	// Both dates use the commit date, which is also what the history is
+	// ordered by.
+	updated := commits[0].committed
+	created := commits[0].committed
+	recent := 0
+	authors := make(map[string]bool)
+	orgs := make(map[string]bool)
+	for _, c := range commits {
+		if c.committed.Before(created) {
+			created = c.committed
+		}
+		if c.committed.After(updated) {
+			updated = c.committed
+		}
+		if now.Sub(c.committed) < legacyCommitLookback {
+			recent++
+		}
+		if c.email == "" {
+			continue
+		}
+		authors[c.email] = true
+		if domain := emaildomain.Domain(c.email); domain != "" && !emaildomain.IsWebmail(domain) {
+			orgs[domain] = true
+		}
+	}
+	set.CreatedAt.Set(created)
+	set.CreatedSince.Set(legacy.TimeDelta(now, created, legacy.SinceDuration))
+	set.UpdatedAt.Set(updated)
+	set.UpdatedSince.Set(legacy.TimeDelta(now, updated, legacy.SinceDuration))
+	set.CommitFrequency.Set(legacy.Round(float64(recent)/52, 2))
+	set.ContributorCount.Set(min(len(authors), legacy.MaxContributorLimit))
+	set.OrgCount.Set(len(orgs))
+
+	gr.logger.Debug("Reading tags")
+	releases, err := gr.recentTagCount(ctx, now.Add(-legacyReleaseLookback))
+	if err != nil {
+		return nil, fmt.Errorf("count tags: %w", err)
+	}
+	set.RecentReleaseCount.Set(releases)
+	return set, nil
+}
+
+func (s *Source) IsSupported(r projectrepo.Repo) bool {
+	_, ok := r.(*repo)
+	return ok
+}
+
+// commits returns every commit reachable from HEAD, newest first.
+func (r *repo) commits(ctx context.Context) ([]commit, error) {
+	out, err := runGit(ctx, r.dir, "log", "--format=%ct%x09%ae", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	var commits []commit
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		fields := strings.SplitN(sc.Text(), "\t", 2)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("malformed log line %q", sc.Text())
+		}
+		committed, err := parseUnix(fields[0])
+		if err != nil {
+			return nil, err
+		}
+		commits = append(commits, commit{
+			committed: committed,
+			email:     strings.ToLower(strings.TrimSpace(fields[1])),
+		})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if len(commits) == 0 {
+		return nil, errors.New("no commits found")
+	}
+	return commits, nil
+}
+
+// recentTagCount returns the number of tags created after cutoff.
+//
+// For annotated tags the tagger date is used, otherwise the date of the
+// tagged commit is used.
+func (r *repo) recentTagCount(ctx context.Context, cutoff time.Time) (int, error) {
+	out, err := runGit(ctx, r.dir, "for-each-ref", "--format=%(creatordate:unix)", "refs/tags")
+	if err != nil {
+		return 0, err
+	}
+	total := 0
+	for _, line := range strings.Fields(string(out)) {
+		t, err := parseUnix(line)
+		if err != nil {
+			return 0, err
+		}
+		if t.After(cutoff) {
+			total++
+		}
+	}
+	return total, nil
+}
+
+func parseUnix(s string) (time.Time, error) {
+	secs, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse timestamp %q: %w", s, err)
+	}
+	return time.Unix(secs, 0).UTC(), nil
+}
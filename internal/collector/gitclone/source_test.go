@@ -0,0 +1,200 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitclone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+
+	"github.com/ossf/criticality_score/v2/internal/collector/projectrepo"
+	"github.com/ossf/criticality_score/v2/internal/collector/signal"
+)
+
+// testSchemes allows the local repositories used by the tests to be cloned.
+var testSchemes = []string{"file"}
+
+// testRepo is a local git repository used as the origin for clones.
+type testRepo struct {
+	t   *testing.T
+	dir string
+}
+
+func newTestRepo(t *testing.T) *testRepo {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	r := &testRepo{t: t, dir: t.TempDir()}
+	r.git(time.Now(), "init", "--quiet", "--initial-branch=main")
+	return r
+}
+
+func (r *testRepo) git(when time.Time, args ...string) {
+	r.t.Helper()
+	date := fmt.Sprintf("%d +0000", when.Unix())
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.dir
+	cmd.Env = append(os.Environ(),
+		"GIT_CONFIG_GLOBAL=/dev/null",
+		"GIT_CONFIG_NOSYSTEM=1",
+		"GIT_AUTHOR_DATE="+date,
+		"GIT_COMMITTER_DATE="+date,
+		"GIT_COMMITTER_NAME=Committer",
+		"GIT_COMMITTER_EMAIL=committer@example.com",
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		r.t.Fatalf("git %v: %v: %s", args, err, out)
+	}
+}
+
+func (r *testRepo) commit(when time.Time, email string) {
+	r.t.Helper()
+	r.git(when, "-c", "user.name=Author", "-c", "user.email="+email,
+		"commit", "--quiet", "--allow-empty", "--message=commit")
+}
+
+func (r *testRepo) url() *url.URL {
+	return &url.URL{Scheme: "file", Path: filepath.ToSlash(r.dir)}
+}
+
+func TestFactoryMatch(t *testing.T) {
+	tests := []struct {
+		url     string
+		schemes []string
+		want    bool
+	}{
+		{url: "https://git.example.com/project.git", schemes: DefaultSchemes, want: true},
+		{url: "http://git.example.com/project.git", schemes: DefaultSchemes, want: true},
+		{url: "git://git.example.com/project", schemes: DefaultSchemes, want: true},
+		{url: "ssh://git@git.example.com/project", schemes: DefaultSchemes, want: false},
+		{url: "ssh://git@git.example.com/project", schemes: []string{"ssh"}, want: true},
+		{url: "file:///srv/git/project", schemes: DefaultSchemes, want: false},
+		{url: "file:///srv/git/project", schemes: testSchemes, want: true},
+		{url: "https://git.example.com/project.git", schemes: testSchemes, want: false},
+		{url: "https://git.example.com/", schemes: DefaultSchemes, want: false},
+		{url: "ftp://git.example.com/project", schemes: []string{"ftp"}, want: false},
+		{url: "project", schemes: DefaultSchemes, want: false},
+	}
+	for _, test := range tests {
+		t.Run(test.url, func(t *testing.T) {
+			f := NewRepoFactory(zaptest.NewLogger(t), t.TempDir(), test.schemes)
+			u, _ := url.Parse(test.url)
+			if got := f.Match(u); got != test.want {
+				t.Fatalf("Match(%s) = %v, want %v", test.url, got, test.want)
+			}
+		})
+	}
+}
+
+func TestFactoryNew_NotFound(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	f := NewRepoFactory(zaptest.NewLogger(t), t.TempDir(), testSchemes)
+	u := &url.URL{Scheme: "file", Path: filepath.ToSlash(filepath.Join(t.TempDir(), "missing"))}
+	_, err := f.New(context.Background(), u)
+	if !errors.Is(err, projectrepo.ErrNoRepoFound) {
+		t.Fatalf("New() = %v, want %v", err, projectrepo.ErrNoRepoFound)
+	}
+}
+
+func TestSource(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	first := now.Add(-800 * 24 * time.Hour)
+	last := now.Add(-2 * 24 * time.Hour)
+
+	origin := newTestRepo(t)
+	origin.commit(first, "alice@example.com")
+	origin.git(first, "tag", "v0.1.0")
+	origin.commit(now.Add(-100*24*time.Hour), "bob@gmail.com")
+	origin.commit(now.Add(-50*24*time.Hour), "carol@corp.example.org")
+	origin.git(now.Add(-50*24*time.Hour), "tag", "--annotate", "--message=release", "v1.0.0")
+	origin.commit(last, "Alice@Example.com")
+
+	f := NewRepoFactory(zaptest.NewLogger(t), t.TempDir(), testSchemes)
+	r, err := f.New(context.Background(), origin.url())
+	if err != nil {
+		t.Fatalf("New() = %v, want no error", err)
+	}
+	src := &Source{}
+	if !src.IsSupported(r) {
+		t.Fatal("IsSupported() = false, want true")
+	}
+
+	// Add another commit and check it is picked up when the cached clone is
+	// reused.
+	origin.commit(last, "dave@users.noreply.github.com")
+	r, err = f.New(context.Background(), origin.url())
+	if err != nil {
+		t.Fatalf("New() = %v, want no error", err)
+	}
+
+	set, err := src.Get(context.Background(), r, "")
+	if err != nil {
+		t.Fatalf("Get() = %v, want no error", err)
+	}
+	s := set.(*signal.RepoSet)
+
+	if got, want := s.URL.Get(), origin.url().String(); got != want {
+		t.Errorf("URL = %q, want %q", got, want)
+	}
+	if got, want := s.CreatedAt.Get(), first; !got.Equal(want) {
+		t.Errorf("CreatedAt = %v, want %v", got, want)
+	}
+	if got, want := s.UpdatedAt.Get(), last; !got.Equal(want) {
+		t.Errorf("UpdatedAt = %v, want %v", got, want)
+	}
+	if got, want := s.CommitFrequency.Get(), 0.08; got != want {
+		t.Errorf("CommitFrequency = %v, want %v", got, want)
+	}
+	if got, want := s.ContributorCount.Get(), 4; got != want {
+		t.Errorf("ContributorCount = %d, want %d", got, want)
+	}
+	if got, want := s.OrgCount.Get(), 2; got != want {
+		t.Errorf("OrgCount = %d, want %d", got, want)
+	}
+	if got, want := s.RecentReleaseCount.Get(), 1; got != want {
+		t.Errorf("RecentReleaseCount = %d, want %d", got, want)
+	}
+}
+
+func TestSource_Empty(t *testing.T) {
+	origin := newTestRepo(t)
+	f := NewRepoFactory(zaptest.NewLogger(t), t.TempDir(), testSchemes)
+	r, err := f.New(context.Background(), origin.url())
+	if err != nil {
+		t.Fatalf("New() = %v, want no error", err)
+	}
+	set, err := (&Source{}).Get(context.Background(), r, "")
+	if err != nil {
+		t.Fatalf("Get() = %v, want no error", err)
+	}
+	s := set.(*signal.RepoSet)
+	if !s.URL.IsSet() {
+		t.Errorf("URL is unset, want set")
+	}
+	if s.CreatedAt.IsSet() {
+		t.Errorf("CreatedAt is set, want unset")
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const (
+	apiPath = "/api/v1"
+
+	// totalHeader is the header Gitea uses to report the total number of
+	// items in a paginated collection.
+	totalHeader = "X-Total-Count"
+
+	// pageLimit is the largest page size supported by the default
+	// configuration of Gitea.
+	pageLimit = 50
+)
+
+var (
+	// errNotFound is returned when the Gitea API responds with a 404.
+	errNotFound = errors.New("not found")
+
+	// errForbidden is returned when the Gitea API responds with a 401 or 403.
+	errForbidden = errors.New("forbidden")
+)
+
+// client is a minimal client for the REST API of a single Gitea or Forgejo
+// instance.
+type client struct {
+	http    *http.Client
+	baseURL string
+}
+
+// newClient returns a client for the Gitea instance hosting the repository
+// at u.
+func newClient(c *http.Client, u *url.URL) *client {
+	base := url.URL{Scheme: u.Scheme, Host: u.Host, Path: apiPath}
+	return &client{
+		http:    c,
+		baseURL: base.String(),
+	}
+}
+
+// get requests the API endpoint at path and parses the JSON response into v.
+//
+// The path must already be escaped. If v is nil the response body is
+// discarded.
+func (c *client) get(ctx context.Context, path string, q url.Values, v any) (*http.Response, error) {
+	u, err := url.Parse(c.baseURL + "/" + path)
+	if err != nil {
+		return nil, fmt.Errorf("parse api url: %w", err)
+	}
+	u.RawQuery = q.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("new request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("gitea request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("%w: %s", errNotFound, u)
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return nil, fmt.Errorf("%w: %s", errForbidden, u)
+	case resp.StatusCode < 200 || 300 <= resp.StatusCode:
+		return nil, fmt.Errorf("gitea request %s: unexpected status %s", u, resp.Status)
+	}
+
+	if v == nil {
+		_, err = io.Copy(io.Discard, resp.Body)
+	} else {
+		err = json.NewDecoder(resp.Body).Decode(v)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading response for %s: %w", u, err)
+	}
+	return resp, nil
+}
+
+// count returns the total number of items in the paginated collection at
+// path, capped to limit.
+func (c *client) count(ctx context.Context, path string, q url.Values, limit int) (int, error) {
+	pq := url.Values{}
+	for k, v := range q {
+		pq[k] = v
+	}
+	pq.Set("limit", "1")
+	var items []json.RawMessage
+	resp, err := c.get(ctx, path, pq, &items)
+	if err != nil {
+		return 0, err
+	}
+	total := len(items)
+	if h := resp.Header.Get(totalHeader); h != "" {
+		total, err = strconv.Atoi(h)
+		if err != nil {
+			return 0, fmt.Errorf("parse %s header: %w", totalHeader, err)
+		}
+	}
+	if total > limit {
+		return limit, nil
+	}
+	return total, nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitea
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/ossf/criticality_score/v2/internal/collector/projectrepo"
+)
+
+type factory struct {
+	client *http.Client
+	logger *zap.Logger
+	hosts  map[string]bool
+}
+
+// NewRepoFactory returns a projectrepo.Factory for repositories hosted on any
+// of the Gitea or Forgejo instances in hosts.
+//
+// Each host is the hostname of an instance, such as "codeberg.org".
+func NewRepoFactory(client *http.Client, logger *zap.Logger, hosts []string) projectrepo.Factory {
+	f := &factory{
+		client: client,
+		logger: logger,
+		hosts:  make(map[string]bool),
+	}
+	for _, h := range hosts {
+		f.hosts[strings.ToLower(h)] = true
+	}
+	return f
+}
+
+func (f *factory) New(ctx context.Context, u *url.URL) (projectrepo.Repo, error) {
+	r := &repo{
+		client:  newClient(f.client, u),
+		origURL: u,
+		logger:  f.logger.With(zap.String("url", u.String())),
+	}
+	if err := r.init(ctx); err != nil {
+		if errors.Is(err, errNotFound) {
+			return nil, fmt.Errorf("%w (%s): %w", projectrepo.ErrNoRepoFound, u, err)
+		} else if errors.Is(err, errForbidden) {
+			return nil, fmt.Errorf("%w (%s): %w", projectrepo.ErrRepoInaccessible, u, err)
+		} else {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func (f *factory) Match(u *url.URL) bool {
+	if !f.hosts[strings.ToLower(u.Hostname())] {
+		return false
+	}
+	owner, name := parseRepoPath(u)
+	return owner != "" && name != ""
+}
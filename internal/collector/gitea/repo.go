@@ -0,0 +1,199 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitea
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// repoData contains the fields of a Gitea repository that are used for
+// collecting signals.
+type repoData struct {
+	Name          string
+	HTMLURL       string `json:"html_url"`
+	DefaultBranch string `json:"default_branch"`
+
+	Owner struct{ Login string }
+
+	// Licenses is only populated by Gitea 1.22 and newer.
+	Licenses []string
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	StarsCount    int `json:"stars_count"`
+	WatchersCount int `json:"watchers_count"`
+	ForksCount    int `json:"forks_count"`
+
+	Archived  bool
+	Mirror    bool
+	Empty     bool
+	HasIssues bool `json:"has_issues"`
+	Fork      bool
+
+	// Parent is only present if the repository is a fork.
+	Parent *struct {
+		HTMLURL string `json:"html_url"`
+	}
+
+	// OriginalURL is the URL the repository was migrated or mirrored from.
+	OriginalURL string `json:"original_url"`
+}
+
+type commitData struct {
+	Commit struct {
+		Author struct {
+			Name  string
+			Email string
+			Date  time.Time
+		}
+	}
+}
+
+// repo implements the projectrepo.Repo interface for a Gitea repository.
+type repo struct {
+	client  *client
+	origURL *url.URL
+	logger  *zap.Logger
+
+	BasicData *repoData
+	realURL   *url.URL
+	created   time.Time
+	updated   time.Time
+}
+
+// URL implements the projectrepo.Repo interface.
+func (r *repo) URL() *url.URL {
+	return r.realURL
+}
+
+func (r *repo) init(ctx context.Context) error {
+	if r.BasicData != nil {
+		// Already finished. Don't init() more than once.
+		return nil
+	}
+	owner, name := parseRepoPath(r.origURL)
+	r.logger.Debug("Fetching basic data from Gitea")
+	data := &repoData{}
+	if _, err := r.client.get(ctx, "repos/"+url.PathEscape(owner)+"/"+url.PathEscape(name), nil, data); err != nil {
+		return err
+	}
+	var err error
+	r.realURL, err = url.Parse(data.HTMLURL)
+	if err != nil {
+		return fmt.Errorf("parse html_url: %w", err)
+	}
+	r.created = data.CreatedAt
+	r.updated = data.UpdatedAt
+	if !data.Empty {
+		r.logger.Debug("Fetching commit times")
+		if err := r.initCommitTimes(ctx, data); err != nil {
+			return err
+		}
+	}
+	// Set BasicData last as it is used to indicate init() has been called.
+	r.BasicData = data
+	return nil
+}
+
+// initCommitTimes determines the created and updated times of the repository
+// based on its commit history.
+//
+// Repositories that are migrated or mirrored may have commits that pre-date
+// the creation of the repository on the Gitea instance.
+func (r *repo) initCommitTimes(ctx context.Context, data *repoData) error {
+	path := "repos/" + url.PathEscape(data.Owner.Login) + "/" + url.PathEscape(data.Name) + "/commits"
+	q := commitQuery(data.DefaultBranch)
+
+	var latest []commitData
+	q.Set("limit", "1")
+	resp, err := r.client.get(ctx, path, q, &latest)
+	if err != nil {
+		return fmt.Errorf("fetch latest commit: %w", err)
+	}
+	if len(latest) == 0 {
+		return nil
+	}
+	r.updated = latest[0].Commit.Author.Date
+
+	// Commits are returned newest first, so the last page contains the
+	// earliest commit.
+	total := resp.Header.Get(totalHeader)
+	if total == "" || total == "1" {
+		return nil
+	}
+	var earliest []commitData
+	q.Set("page", total)
+	if _, err := r.client.get(ctx, path, q, &earliest); err != nil {
+		return fmt.Errorf("fetch earliest commit: %w", err)
+	}
+	if len(earliest) > 0 && earliest[0].Commit.Author.Date.Before(r.created) {
+		r.created = earliest[0].Commit.Author.Date
+	}
+	return nil
+}
+
+// ParentURL implements the projectrepo.Fork interface.
+func (r *repo) ParentURL() *url.URL {
+	if r.BasicData.Parent == nil {
+		return nil
+	}
+	u, err := url.Parse(r.BasicData.Parent.HTMLURL)
+	if err != nil {
+		return nil
+	}
+	return u
+}
+
+func (r *repo) path() string {
+	return "repos/" + url.PathEscape(r.BasicData.Owner.Login) + "/" + url.PathEscape(r.BasicData.Name)
+}
+
+func (r *repo) createdAt() time.Time {
+	return r.created
+}
+
+func (r *repo) updatedAt() time.Time {
+	return r.updated
+}
+
+// commitQuery returns the query parameters for listing the commits on branch
+// without the expensive per-commit details.
+func commitQuery(branch string) url.Values {
+	q := url.Values{
+		"stat":         {"false"},
+		"verification": {"false"},
+		"files":        {"false"},
+	}
+	if branch != "" {
+		q.Set("sha", branch)
+	}
+	return q
+}
+
+// parseRepoPath returns the owner and name of the repository at u.
+func parseRepoPath(u *url.URL) (owner, name string) {
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 2 {
+		return "", ""
+	}
+	return parts[0], strings.TrimSuffix(parts[1], ".git")
+}
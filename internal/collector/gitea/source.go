@@ -0,0 +1,256 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gitea provides a projectrepo.Factory and signal Sources for
+// repositories hosted on Gitea and Forgejo instances, such as Codeberg.
+package gitea
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ossf/criticality_score/v2/internal/collector/github/legacy"
+	"github.com/ossf/criticality_score/v2/internal/collector/projectrepo"
+	"github.com/ossf/criticality_score/v2/internal/collector/signal"
+)
+
+const (
+	legacyReleaseLookbackDays = 365
+	legacyReleaseLookback     = legacyReleaseLookbackDays * 24 * time.Hour
+	legacyCommitLookback      = 365 * 24 * time.Hour
+
+	// contributorCommitLimit is the maximum number of commits examined when
+	// counting contributors, as Gitea has no API for listing contributors.
+	contributorCommitLimit = 1000
+
+	maxCommitsLimit = 52 * 1000
+	maxTagsLimit    = 10000
+)
+
+type RepoSource struct{}
+
+func (rc *RepoSource) EmptySet() signal.Set {
+	return &signal.RepoSet{}
+}
+
+func (rc *RepoSource) Get(ctx context.Context, r projectrepo.Repo, _ string) (signal.Set, error) {
+	gr, ok := r.(*repo)
+	if !ok {
+		return nil, errors.New("project is not a gitea project")
+	}
+	now := time.Now()
+
+	s := &signal.RepoSet{
+		URL:          signal.Val(r.URL().String()),
+		StarCount:    signal.Val(gr.BasicData.StarsCount),
+		CreatedAt:    signal.Val(gr.createdAt()),
+		CreatedSince: signal.Val(legacy.TimeDelta(now, gr.createdAt(), legacy.SinceDuration)),
+		UpdatedAt:    signal.Val(gr.updatedAt()),
+		UpdatedSince: signal.Val(legacy.TimeDelta(now, gr.updatedAt(), legacy.SinceDuration)),
+
+		WatcherCount:     signal.Val(gr.BasicData.WatchersCount),
+		ForkCount:        signal.Val(gr.BasicData.ForksCount),
+		IsArchived:       signal.Val(gr.BasicData.Archived),
+		IsMirror:         signal.Val(gr.BasicData.Mirror),
+		IsEmpty:          signal.Val(gr.BasicData.Empty),
+		HasIssuesEnabled: signal.Val(gr.BasicData.HasIssues),
+		IsFork:           signal.Val(gr.BasicData.Fork),
+	}
+	if gr.BasicData.Mirror {
+		s.MirrorURL.Set(gr.BasicData.OriginalURL)
+	}
+	if gr.BasicData.Parent != nil {
+		s.ParentURL.Set(gr.BasicData.Parent.HTMLURL)
+	}
+	if len(gr.BasicData.Licenses) > 0 {
+		s.License.Set(strings.Join(gr.BasicData.Licenses, ", "))
+	}
+
+	gr.logger.Debug("Fetching languages")
+	var languages map[string]int64
+	if _, err := gr.client.get(ctx, gr.path()+"/languages", nil, &languages); err != nil {
+		return nil, fmt.Errorf("fetch languages: %w", err)
+	}
+	s.Language.Set(primaryLanguage(languages))
+
+	if !gr.BasicData.Empty {
+		gr.logger.Debug("Fetching contributors")
+		contributors, err := gr.contributorCount(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("count contributors: %w", err)
+		}
+		s.ContributorCount.Set(contributors)
+
+		gr.logger.Debug("Fetching recent commits")
+		q := commitQuery(gr.BasicData.DefaultBranch)
+		q.Set("since", now.Add(-legacyCommitLookback).UTC().Format(time.RFC3339))
+		commits, err := gr.client.count(ctx, gr.path()+"/commits", q, maxCommitsLimit)
+		if err != nil {
+			return nil, fmt.Errorf("count commits: %w", err)
+		}
+		s.CommitFrequency.Set(legacy.Round(float64(commits)/52, 2))
+	} else {
+		s.ContributorCount.Set(0)
+		s.CommitFrequency.Set(0)
+	}
+
+	gr.logger.Debug("Fetching releases")
+	releaseCount, err := gr.recentReleaseCount(ctx, now.Add(-legacyReleaseLookback))
+	if err != nil {
+		return nil, fmt.Errorf("count releases: %w", err)
+	}
+	if releaseCount != 0 {
+		s.RecentReleaseCount.Set(releaseCount)
+	} else {
+		// Fallback to estimating the number of releases from the tags, in the
+		// same way the GitHub source does.
+		tags, err := gr.client.count(ctx, gr.path()+"/tags", nil, maxTagsLimit)
+		if err != nil {
+			return nil, fmt.Errorf("count tags: %w", err)
+		}
+		daysSinceCreated := int(now.Sub(gr.createdAt()).Hours()) / 24
+		if daysSinceCreated > 0 {
+			s.RecentReleaseCount.Set((tags * legacyReleaseLookbackDays) / daysSinceCreated)
+		} else {
+			s.RecentReleaseCount.Set(0)
+		}
+	}
+	return s, nil
+}
+
+func (rc *RepoSource) IsSupported(p projectrepo.Repo) bool {
+	_, ok := p.(*repo)
+	return ok
+}
+
+// contributorCount returns the number of distinct commit authors in the most
+// recent contributorCommitLimit commits on the default branch.
+func (r *repo) contributorCount(ctx context.Context) (int, error) {
+	authors := make(map[string]bool)
+	q := commitQuery(r.BasicData.DefaultBranch)
+	q.Set("limit", fmt.Sprint(pageLimit))
+	for page := 1; page*pageLimit <= contributorCommitLimit; page++ {
+		q.Set("page", fmt.Sprint(page))
+		var commits []commitData
+		if _, err := r.client.get(ctx, r.path()+"/commits", q, &commits); err != nil {
+			return 0, err
+		}
+		for _, c := range commits {
+			author := strings.ToLower(c.Commit.Author.Email)
+			if author == "" {
+				author = c.Commit.Author.Name
+			}
+			authors[author] = true
+		}
+		if len(commits) < pageLimit {
+			break
+		}
+	}
+	return len(authors), nil
+}
+
+// recentReleaseCount returns the number of releases published after cutoff.
+func (r *repo) recentReleaseCount(ctx context.Context, cutoff time.Time) (int, error) {
+	q := url.Values{
+		"draft": {"false"},
+		"limit": {fmt.Sprint(pageLimit)},
+	}
+	total := 0
+	for page := 1; ; page++ {
+		q.Set("page", fmt.Sprint(page))
+		var releases []struct {
+			PublishedAt time.Time `json:"published_at"`
+		}
+		if _, err := r.client.get(ctx, r.path()+"/releases", q, &releases); err != nil {
+			return 0, err
+		}
+		for _, rel := range releases {
+			if rel.PublishedAt.Before(cutoff) {
+				return total, nil
+			}
+			total++
+		}
+		if len(releases) < pageLimit {
+			return total, nil
+		}
+	}
+}
+
+// primaryLanguage returns the language with the most bytes of code in the
+// repository.
+func primaryLanguage(languages map[string]int64) string {
+	name := ""
+	var size int64
+	for l, s := range languages {
+		if s > size || (s == size && l < name) {
+			name = l
+			size = s
+		}
+	}
+	return name
+}
+
+type IssuesSource struct{}
+
+func (ic *IssuesSource) EmptySet() signal.Set {
+	return &signal.IssuesSet{}
+}
+
+func (ic *IssuesSource) Get(ctx context.Context, r projectrepo.Repo, _ string) (signal.Set, error) {
+	gr, ok := r.(*repo)
+	if !ok {
+		return nil, errors.New("project is not a gitea project")
+	}
+	s := &signal.IssuesSet{}
+	since := time.Now().UTC().Add(-legacy.IssueLookback).Format(time.RFC3339)
+
+	// Like GitHub, Gitea includes pull requests when listing issues unless
+	// a type is specified.
+	gr.logger.Debug("Fetching closed issues")
+	closed, err := gr.client.count(ctx, gr.path()+"/issues", url.Values{"state": {"closed"}, "since": {since}}, legacy.MaxIssuesLimit)
+	if err != nil {
+		return nil, fmt.Errorf("count closed issues: %w", err)
+	}
+	s.ClosedCount.Set(closed)
+
+	gr.logger.Debug("Fetching updated issues")
+	up, err := gr.client.count(ctx, gr.path()+"/issues", url.Values{"state": {"all"}, "since": {since}}, legacy.MaxIssuesLimit)
+	if err != nil {
+		return nil, fmt.Errorf("count updated issues: %w", err)
+	}
+	s.UpdatedCount.Set(up)
+
+	if up == 0 {
+		s.CommentFrequency.Set(0)
+		return s, nil
+	}
+
+	gr.logger.Debug("Fetching comment frequency")
+	comments, err := gr.client.count(ctx, gr.path()+"/issues/comments", url.Values{"since": {since}}, math.MaxInt)
+	if err != nil {
+		return nil, fmt.Errorf("count comments: %w", err)
+	}
+	s.CommentFrequency.Set(legacy.Round(float64(comments)/float64(up), 2))
+	return s, nil
+}
+
+func (ic *IssuesSource) IsSupported(r projectrepo.Repo) bool {
+	_, ok := r.(*repo)
+	return ok
+}
@@ -0,0 +1,248 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+
+	"github.com/ossf/criticality_score/v2/internal/collector/projectrepo"
+	"github.com/ossf/criticality_score/v2/internal/collector/signal"
+)
+
+// fakeGitea is a minimal stand-in for the Gitea REST API serving a single
+// repository.
+type fakeGitea struct {
+	*httptest.Server
+	created time.Time
+}
+
+func newFakeGitea(t *testing.T) *fakeGitea {
+	t.Helper()
+	f := &fakeGitea{
+		created: time.Now().UTC().Add(-400 * 24 * time.Hour).Truncate(time.Second),
+	}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.serve))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func commit(email string, date time.Time) map[string]any {
+	return map[string]any{
+		"commit": map[string]any{
+			"author": map[string]any{"email": email, "date": date},
+		},
+	}
+}
+
+func (f *fakeGitea) serve(w http.ResponseWriter, r *http.Request) {
+	write := func(v any, total int) {
+		if total >= 0 {
+			w.Header().Set(totalHeader, fmt.Sprint(total))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(v)
+	}
+	q := r.URL.Query()
+	now := time.Now().UTC()
+	switch r.URL.Path {
+	case "/api/v1/repos/owner/repo":
+		write(map[string]any{
+			"name":           "repo",
+			"owner":          map[string]any{"login": "owner"},
+			"html_url":       f.URL + "/owner/repo",
+			"default_branch": "main",
+			"licenses":       []string{"MIT"},
+			"created_at":     f.created,
+			"updated_at":     now,
+			"stars_count":    33,
+			"watchers_count": 5,
+			"forks_count":    2,
+			"archived":       true,
+			"has_issues":     true,
+		}, -1)
+	case "/api/v1/repos/owner/repo/languages":
+		write(map[string]int64{"C": 1000, "Python": 300}, -1)
+	case "/api/v1/repos/owner/repo/commits":
+		switch {
+		case q.Get("since") != "":
+			write([]any{commit("a@example.com", now)}, 156)
+		case q.Get("page") == "4":
+			write([]any{commit("a@example.com", f.created.Add(-24*time.Hour))}, 4)
+		case q.Get("limit") == "1":
+			write([]any{commit("a@example.com", now.Add(-72*time.Hour))}, 4)
+		default:
+			write([]any{
+				commit("a@example.com", now),
+				commit("B@example.com", now),
+				commit("b@example.com", now),
+				commit("c@example.com", now),
+			}, 4)
+		}
+	case "/api/v1/repos/owner/repo/releases":
+		write([]any{
+			map[string]any{"published_at": now.Add(-24 * time.Hour)},
+			map[string]any{"published_at": now.Add(-500 * 24 * time.Hour)},
+		}, 2)
+	case "/api/v1/repos/owner/repo/issues":
+		if q.Get("state") == "closed" {
+			write([]any{map[string]any{}}, 3)
+		} else {
+			write([]any{map[string]any{}}, 8)
+		}
+	case "/api/v1/repos/owner/repo/issues/comments":
+		write([]any{map[string]any{}}, 10)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (f *fakeGitea) factory(t *testing.T) projectrepo.Factory {
+	t.Helper()
+	u, _ := url.Parse(f.URL)
+	return NewRepoFactory(f.Client(), zaptest.NewLogger(t), []string{u.Hostname()})
+}
+
+func (f *fakeGitea) repo(t *testing.T) projectrepo.Repo {
+	t.Helper()
+	u, _ := url.Parse(f.URL + "/owner/repo.git")
+	r, err := f.factory(t).New(context.Background(), u)
+	if err != nil {
+		t.Fatalf("New() = %v, want no error", err)
+	}
+	return r
+}
+
+func TestFactoryMatch(t *testing.T) {
+	f := NewRepoFactory(http.DefaultClient, zaptest.NewLogger(t), []string{"codeberg.org"})
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{url: "https://codeberg.org/owner/repo", want: true},
+		{url: "https://Codeberg.org/owner/repo.git", want: true},
+		{url: "https://codeberg.org/owner", want: false},
+		{url: "https://gitlab.com/owner/repo", want: false},
+	}
+	for _, test := range tests {
+		t.Run(test.url, func(t *testing.T) {
+			u, _ := url.Parse(test.url)
+			if got := f.Match(u); got != test.want {
+				t.Fatalf("Match(%s) = %v, want %v", test.url, got, test.want)
+			}
+		})
+	}
+}
+
+func TestFactoryNew_NotFound(t *testing.T) {
+	f := newFakeGitea(t)
+	u, _ := url.Parse(f.URL + "/owner/missing")
+	_, err := f.factory(t).New(context.Background(), u)
+	if !errors.Is(err, projectrepo.ErrNoRepoFound) {
+		t.Fatalf("New() = %v, want %v", err, projectrepo.ErrNoRepoFound)
+	}
+}
+
+func TestRepoSource(t *testing.T) {
+	f := newFakeGitea(t)
+	r := f.repo(t)
+	src := &RepoSource{}
+	if !src.IsSupported(r) {
+		t.Fatal("IsSupported() = false, want true")
+	}
+	set, err := src.Get(context.Background(), r, "")
+	if err != nil {
+		t.Fatalf("Get() = %v, want no error", err)
+	}
+	s := set.(*signal.RepoSet)
+
+	if got, want := s.URL.Get(), f.URL+"/owner/repo"; got != want {
+		t.Errorf("URL = %q, want %q", got, want)
+	}
+	if got, want := s.Language.Get(), "C"; got != want {
+		t.Errorf("Language = %q, want %q", got, want)
+	}
+	if got, want := s.License.Get(), "MIT"; got != want {
+		t.Errorf("License = %q, want %q", got, want)
+	}
+	if got, want := s.StarCount.Get(), 33; got != want {
+		t.Errorf("StarCount = %d, want %d", got, want)
+	}
+	if got, want := s.WatcherCount.Get(), 5; got != want {
+		t.Errorf("WatcherCount = %d, want %d", got, want)
+	}
+	if got, want := s.IsArchived.Get(), true; got != want {
+		t.Errorf("IsArchived = %v, want %v", got, want)
+	}
+	if got, want := s.HasIssuesEnabled.Get(), true; got != want {
+		t.Errorf("HasIssuesEnabled = %v, want %v", got, want)
+	}
+	if s.MirrorURL.IsSet() {
+		t.Errorf("MirrorURL is set, want unset")
+	}
+	if got, want := s.ForkCount.Get(), 2; got != want {
+		t.Errorf("ForkCount = %d, want %d", got, want)
+	}
+	if got, want := s.IsFork.Get(), false; got != want {
+		t.Errorf("IsFork = %v, want %v", got, want)
+	}
+	if s.ParentURL.IsSet() {
+		t.Errorf("ParentURL is set, want unset")
+	}
+	if u := r.(projectrepo.Fork).ParentURL(); u != nil {
+		t.Errorf("ParentURL() = %v, want nil", u)
+	}
+	if got, want := s.CreatedAt.Get(), f.created.Add(-24*time.Hour); !got.Equal(want) {
+		t.Errorf("CreatedAt = %v, want %v", got, want)
+	}
+	if got, want := s.ContributorCount.Get(), 3; got != want {
+		t.Errorf("ContributorCount = %d, want %d", got, want)
+	}
+	if got, want := s.CommitFrequency.Get(), 3.0; got != want {
+		t.Errorf("CommitFrequency = %v, want %v", got, want)
+	}
+	if got, want := s.RecentReleaseCount.Get(), 1; got != want {
+		t.Errorf("RecentReleaseCount = %d, want %d", got, want)
+	}
+}
+
+func TestIssuesSource(t *testing.T) {
+	f := newFakeGitea(t)
+	r := f.repo(t)
+	set, err := (&IssuesSource{}).Get(context.Background(), r, "")
+	if err != nil {
+		t.Fatalf("Get() = %v, want no error", err)
+	}
+	s := set.(*signal.IssuesSet)
+
+	if got, want := s.ClosedCount.Get(), 3; got != want {
+		t.Errorf("ClosedCount = %d, want %d", got, want)
+	}
+	if got, want := s.UpdatedCount.Get(), 8; got != want {
+		t.Errorf("UpdatedCount = %d, want %d", got, want)
+	}
+	if got, want := s.CommentFrequency.Get(), 1.25; got != want {
+		t.Errorf("CommentFrequency = %v, want %v", got, want)
+	}
+}
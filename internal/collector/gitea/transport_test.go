@@ -0,0 +1,95 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitea
+
+import (
+	"net/http"
+	"testing"
+)
+
+// headerRecorder is an http.RoundTripper that records the Authorization header
+// of the last request.
+type headerRecorder struct {
+	token string
+}
+
+func (rt *headerRecorder) RoundTrip(r *http.Request) (*http.Response, error) {
+	rt.token = r.Header.Get("Authorization")
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: r}, nil
+}
+
+func TestNewTransport_Tokens(t *testing.T) {
+	//nolint:govet
+	tests := []struct {
+		name       string
+		token      string
+		hostTokens string
+		url        string
+		want       string
+	}{
+		{
+			name:  "default token sent to codeberg.org",
+			token: "abc",
+			url:   "https://codeberg.org/api/v1/repos/owner/repo",
+			want:  "token abc",
+		},
+		{
+			name:  "default token not sent to other hosts",
+			token: "abc",
+			url:   "https://gitea.example.com/api/v1/repos/owner/repo",
+			want:  "",
+		},
+		{
+			name:       "host token sent to its host",
+			token:      "abc",
+			hostTokens: "gitea.example.com=def, other.example.com=ghi",
+			url:        "https://Gitea.example.com/api/v1/repos/owner/repo",
+			want:       "token def",
+		},
+		{
+			name:       "host token overrides default token",
+			token:      "abc",
+			hostTokens: "codeberg.org=def",
+			url:        "https://codeberg.org/api/v1/repos/owner/repo",
+			want:       "token def",
+		},
+		{
+			name:       "malformed host tokens ignored",
+			hostTokens: "gitea.example.com,=abc,other.example.com=",
+			url:        "https://gitea.example.com/api/v1/repos/owner/repo",
+			want:       "",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("GITEA_AUTH_TOKEN", test.token)
+			t.Setenv("GITEA_TOKEN", "")
+			t.Setenv(hostTokensEnvVar, test.hostTokens)
+			rec := &headerRecorder{}
+			req, err := http.NewRequest(http.MethodGet, test.url, nil)
+			if err != nil {
+				t.Fatalf("NewRequest() = %v, want no error", err)
+			}
+			resp, err := NewTransport(rec).RoundTrip(req)
+			if err != nil {
+				t.Fatalf("RoundTrip() = %v, want no error", err)
+			}
+			resp.Body.Close()
+			if rec.token != test.want {
+				t.Errorf("Authorization header = %q, want %q", rec.token, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ossf/criticality_score/v2/internal/collector/github/legacy"
+	"github.com/ossf/criticality_score/v2/internal/collector/projectrepo"
+	"github.com/ossf/criticality_score/v2/internal/collector/signal"
+)
+
+const (
+	activityWeeks = 52
+	week          = 7 * 24 * time.Hour
+
+	// quarterWeeks is the number of weeks in a quarter, rounded down.
+	quarterWeeks = activityWeeks / 4
+)
+
+type commitActivitySet struct {
+	// Weekly is the number of commits to the default branch in each of the
+	// last 52 weeks, oldest first. If the commit history is truncated only
+	// the weeks it covers are included.
+	Weekly signal.Detail[[]int] `desc:"Number of commits to the default branch in each week, oldest first." unit:"count" lookback:"52 weeks" source:"GitHub GraphQL API"`
+
+	// Slope is the slope of the line of best fit through the weekly commit
+	// counts. A positive slope indicates activity is increasing.
+	Slope signal.Field[float64] `desc:"Slope of the line of best fit through the weekly commit counts." unit:"commits/week" lookback:"52 weeks" source:"GitHub GraphQL API"`
+
+	// ZeroWeeks is the number of weeks without any commits.
+	ZeroWeeks signal.Field[int] `desc:"Number of weeks without any commits." unit:"weeks" lookback:"52 weeks" source:"GitHub GraphQL API"`
+
+	// QuarterRatio is the number of commits in the last 13 weeks divided by
+	// the number of commits in the 13 weeks before that.
+	QuarterRatio signal.Field[float64] `desc:"Number of commits in the last 13 weeks divided by the number in the 13 weeks before." unit:"ratio" lookback:"26 weeks" source:"GitHub GraphQL API"`
+}
+
+func (s *commitActivitySet) Namespace() signal.Namespace {
+	return signal.Namespace("commit_activity")
+}
+
+// CommitActivitySource collects the weekly number of commits to the default
+// branch of a GitHub repository over the last year, and signals derived from
+// it.
+//
+// The commit history of repositories with no commits in the last year is not
+// fetched.
+type CommitActivitySource struct{}
+
+func (cs *CommitActivitySource) EmptySet() signal.Set {
+	return &commitActivitySet{}
+}
+
+func (cs *CommitActivitySource) IsSupported(r projectrepo.Repo) bool {
+	_, ok := r.(*repo)
+	return ok
+}
+
+func (cs *CommitActivitySource) Get(ctx context.Context, r projectrepo.Repo, _ string) (signal.Set, error) {
+	ghr, ok := r.(*repo)
+	if !ok {
+		return nil, errors.New("project is not a github project")
+	}
+	if ghr.BasicData.IsEmpty {
+		return &commitActivitySet{}, nil
+	}
+	end := time.Now().UTC()
+	since := end.Add(-activityWeeks * week)
+	if isDormant(ghr, since) {
+		ghr.logger.Debug("Skipping commit history for dormant repository")
+		return commitActivityStats(make([]int, activityWeeks)), nil
+	}
+
+	ghr.logger.Debug("Fetching commit history")
+	commits, err := fetchCommitHistory(ctx, ghr.client, ghr.owner(), ghr.name(), since)
+	if err != nil {
+		return nil, fmt.Errorf("fetch commit history: %w", err)
+	}
+	if len(commits) == maxHistoryCommits {
+		// The history was truncated, so drop the weeks that are not fully
+		// covered by the commits fetched.
+		oldest := commits[len(commits)-1].CommittedDate
+		since = since.Add((oldest.Sub(since)/week + 1) * week)
+	}
+	return commitActivityStats(weeklyCommitCounts(commits, since, end)), nil
+}
+
+// isDormant returns true if the last commit to the default branch of the
+// repository was committed before since.
+//
+// The commit date is used, rather than the author date, as it is what the
+// commit history is filtered by.
+func isDormant(r *repo, since time.Time) bool {
+	last := r.lastCommittedAt()
+	return !last.IsZero() && last.Before(since)
+}
+
+// weeklyCommitCounts returns the number of commits in each whole week between
+// since and end, oldest first.
+func weeklyCommitCounts(commits []commit, since, end time.Time) []int {
+	weeks := int(end.Sub(since) / week)
+	if weeks <= 0 {
+		return nil
+	}
+	weekly := make([]int, weeks)
+	for i := range commits {
+		t := commits[i].CommittedDate
+		if t.Before(since) {
+			continue
+		}
+		if w := int(t.Sub(since) / week); w < weeks {
+			weekly[w]++
+		}
+	}
+	return weekly
+}
+
+// commitActivityStats calculates the commit activity signals for the weekly
+// commit counts.
+func commitActivityStats(weekly []int) *commitActivitySet {
+	s := &commitActivitySet{}
+	s.Weekly.Set(weekly)
+
+	zero := 0
+	for _, n := range weekly {
+		if n == 0 {
+			zero++
+		}
+	}
+	s.ZeroWeeks.Set(zero)
+	s.Slope.Set(legacy.Round(slope(weekly), 2))
+
+	if len(weekly) >= 2*quarterWeeks {
+		last := sum(weekly[len(weekly)-quarterWeeks:])
+		prev := sum(weekly[len(weekly)-2*quarterWeeks : len(weekly)-quarterWeeks])
+		if prev > 0 {
+			s.QuarterRatio.Set(legacy.Round(float64(last)/float64(prev), 2))
+		}
+	}
+	return s
+}
+
+// slope returns the slope of the least squares line of best fit through
+// values, where the x coordinate is the index of each value.
+func slope(values []int) float64 {
+	n := float64(len(values))
+	if n < 2 {
+		return 0
+	}
+	meanX := (n - 1) / 2
+	meanY := float64(sum(values)) / n
+	var num, den float64
+	for i, v := range values {
+		dx := float64(i) - meanX
+		num += dx * (float64(v) - meanY)
+		den += dx * dx
+	}
+	return num / den
+}
+
+func sum(values []int) int {
+	total := 0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
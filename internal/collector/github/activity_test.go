@@ -0,0 +1,163 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCommitActivityStats(t *testing.T) {
+	weekly := make([]int, activityWeeks)
+	for i := range weekly {
+		weekly[i] = i / 13
+	}
+	weekly[0] = 0
+	weekly[40] = 0
+
+	s := commitActivityStats(weekly)
+	if got := s.Weekly.Get(); !reflect.DeepEqual(got, weekly) {
+		t.Errorf("Weekly = %v, want %v", got, weekly)
+	}
+	if got, want := s.ZeroWeeks.Get(), 14; got != want {
+		t.Errorf("ZeroWeeks = %d, want %d", got, want)
+	}
+	if got, want := s.Slope.Get(), 0.07; got != want {
+		t.Errorf("Slope = %v, want %v", got, want)
+	}
+	if got, want := s.QuarterRatio.Get(), 1.38; got != want {
+		t.Errorf("QuarterRatio = %v, want %v", got, want)
+	}
+}
+
+func TestCommitActivityStats_NoCommits(t *testing.T) {
+	s := commitActivityStats(make([]int, activityWeeks))
+	if got, want := s.ZeroWeeks.Get(), activityWeeks; got != want {
+		t.Errorf("ZeroWeeks = %d, want %d", got, want)
+	}
+	if got, want := s.Slope.Get(), 0.0; got != want {
+		t.Errorf("Slope = %v, want %v", got, want)
+	}
+	if s.QuarterRatio.IsSet() {
+		t.Errorf("QuarterRatio is set, want unset")
+	}
+}
+
+func TestWeeklyCommitCounts(t *testing.T) {
+	since := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	commitAt := func(d time.Duration) commit {
+		return commit{CommittedDate: since.Add(d)}
+	}
+	//nolint:govet
+	tests := []struct {
+		name    string
+		commits []commit
+		end     time.Time
+		want    []int
+	}{
+		{
+			name: "no commits",
+			end:  since.Add(3 * week),
+			want: []int{0, 0, 0},
+		},
+		{
+			name: "commits in each week",
+			commits: []commit{
+				commitAt(2*week + time.Hour),
+				commitAt(2 * week),
+				commitAt(week - time.Second),
+				commitAt(0),
+			},
+			end:  since.Add(3 * week),
+			want: []int{2, 0, 2},
+		},
+		{
+			name: "partial week ignored",
+			commits: []commit{
+				commitAt(2*week + time.Hour),
+				commitAt(time.Hour),
+			},
+			end:  since.Add(2*week + 2*time.Hour),
+			want: []int{1, 0},
+		},
+		{
+			name: "commits before since ignored",
+			commits: []commit{
+				commitAt(time.Hour),
+				commitAt(-time.Hour),
+			},
+			end:  since.Add(week),
+			want: []int{1},
+		},
+		{
+			name: "end before since",
+			end:  since.Add(-week),
+			want: nil,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := weeklyCommitCounts(test.commits, since, test.end)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("weeklyCommitCounts() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestIsDormant(t *testing.T) {
+	since := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	//nolint:govet
+	tests := []struct {
+		name      string
+		authored  time.Time
+		committed time.Time
+		want      bool
+	}{
+		{
+			name: "no last commit",
+			want: false,
+		},
+		{
+			name:      "committed after since",
+			authored:  since.Add(time.Hour),
+			committed: since.Add(time.Hour),
+			want:      false,
+		},
+		{
+			name:      "committed before since",
+			authored:  since.Add(-time.Hour),
+			committed: since.Add(-time.Hour),
+			want:      true,
+		},
+		{
+			name:      "authored before since, committed after",
+			authored:  since.Add(-time.Hour),
+			committed: since.Add(time.Hour),
+			want:      false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := &repo{BasicData: &basicRepoData{}}
+			r.BasicData.DefaultBranchRef.Target.Commit.AuthoredDate = test.authored
+			r.BasicData.DefaultBranchRef.Target.Commit.CommittedDate = test.committed
+			if got := isDormant(r, since); got != test.want {
+				t.Errorf("isDormant() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/ossf/criticality_score/v2/internal/collector/projectrepo"
+	"github.com/ossf/criticality_score/v2/internal/collector/signal"
+	"github.com/ossf/criticality_score/v2/internal/githubapi"
+)
+
+const (
+	advisoriesPerPage = 100
+
+	// advisorySeverityLookback is the period used for finding the maximum
+	// severity of recent advisories.
+	advisorySeverityLookback = 2 * 365 * 24 * time.Hour
+)
+
+// severityLevels maps the severity of an advisory to a number that can be
+// compared and scored.
+var severityLevels = map[string]int{
+	"low":      1,
+	"medium":   2,
+	"high":     3,
+	"critical": 4,
+}
+
+type advisoriesSet struct {
+	// PublishedCount is the number of security advisories published by the
+	// repository.
+	PublishedCount signal.Field[int] `desc:"Number of security advisories published by the repository." unit:"count" source:"GitHub REST API"`
+
+	// MaxSeverity2y is the highest severity of the advisories published in
+	// the last 2 years, from 1 (low) to 4 (critical).
+	MaxSeverity2y signal.Field[int] `signal:"max_severity_2y" desc:"Highest severity of the published security advisories, from 1 (low) to 4 (critical)." unit:"level" lookback:"2 years" source:"GitHub REST API"`
+
+	// HasPublished is true if the repository has published at least one
+	// security advisory.
+	HasPublished signal.Field[bool] `desc:"Whether the repository has published a security advisory." source:"GitHub REST API"`
+}
+
+func (s *advisoriesSet) Namespace() signal.Namespace {
+	return signal.Namespace("advisories")
+}
+
+// advisory is a security advisory published by a repository.
+type advisory struct {
+	GHSAID      string    `json:"ghsa_id"`
+	Severity    string    `json:"severity"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// AdvisoriesSource collects signals about the GitHub Security Advisories
+// (GHSA) published by a GitHub repository.
+type AdvisoriesSource struct{}
+
+func (as *AdvisoriesSource) EmptySet() signal.Set {
+	return &advisoriesSet{}
+}
+
+func (as *AdvisoriesSource) IsSupported(r projectrepo.Repo) bool {
+	_, ok := r.(*repo)
+	return ok
+}
+
+func (as *AdvisoriesSource) Get(ctx context.Context, r projectrepo.Repo, _ string) (signal.Set, error) {
+	ghr, ok := r.(*repo)
+	if !ok {
+		return nil, errors.New("project is not a github project")
+	}
+	ghr.logger.Debug("Fetching security advisories")
+	advisories, err := fetchAdvisories(ctx, ghr.client, ghr.owner(), ghr.name())
+	switch c := githubapi.ErrorResponseStatusCode(err); {
+	case c == http.StatusForbidden || c == http.StatusNotFound:
+		// The advisories are not visible to the client, so leave them unset.
+		return &advisoriesSet{}, nil
+	case err != nil:
+		return nil, fmt.Errorf("fetch security advisories: %w", err)
+	}
+	return advisoryStats(advisories, time.Now().UTC().Add(-advisorySeverityLookback)), nil
+}
+
+// fetchAdvisories returns all the published security advisories for the
+// repository.
+//
+// The repository advisories API is not available with GraphQL, so the REST
+// API is used.
+func fetchAdvisories(ctx context.Context, c *githubapi.Client, owner, name string) ([]advisory, error) {
+	q := url.Values{
+		"state":    {"published"},
+		"per_page": {fmt.Sprint(advisoriesPerPage)},
+	}
+	var advisories []advisory
+	for {
+		u := fmt.Sprintf("repos/%s/%s/security-advisories?%s", owner, name, q.Encode())
+		req, err := c.Rest().NewRequest(http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		var page []advisory
+		resp, err := c.Rest().Do(ctx, req, &page)
+		if err != nil {
+			return nil, err
+		}
+		advisories = append(advisories, page...)
+		if resp.After == "" {
+			return advisories, nil
+		}
+		q.Set("after", resp.After)
+	}
+}
+
+// advisoryStats calculates the advisory signals for the published advisories.
+func advisoryStats(advisories []advisory, since time.Time) *advisoriesSet {
+	s := &advisoriesSet{
+		PublishedCount: signal.Val(len(advisories)),
+		HasPublished:   signal.Val(len(advisories) > 0),
+	}
+	maxSeverity := 0
+	recent := false
+	for _, a := range advisories {
+		if a.PublishedAt.Before(since) {
+			continue
+		}
+		recent = true
+		maxSeverity = max(maxSeverity, severityLevels[a.Severity])
+	}
+	if recent {
+		s.MaxSeverity2y.Set(maxSeverity)
+	}
+	return s
+}
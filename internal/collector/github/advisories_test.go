@@ -0,0 +1,64 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAdvisoryStats(t *testing.T) {
+	now := time.Now().UTC()
+	since := now.Add(-advisorySeverityLookback)
+	daysAgo := func(d int) time.Time {
+		return now.Add(-time.Duration(d) * 24 * time.Hour)
+	}
+	advisories := []advisory{
+		{GHSAID: "GHSA-1", Severity: "medium", PublishedAt: daysAgo(30)},
+		{GHSAID: "GHSA-2", Severity: "high", PublishedAt: daysAgo(400)},
+		{GHSAID: "GHSA-3", Severity: "critical", PublishedAt: daysAgo(1000)},
+		{GHSAID: "GHSA-4", Severity: "", PublishedAt: daysAgo(10)},
+	}
+
+	s := advisoryStats(advisories, since)
+	if got, want := s.PublishedCount.Get(), 4; got != want {
+		t.Errorf("PublishedCount = %d, want %d", got, want)
+	}
+	if got, want := s.MaxSeverity2y.Get(), 3; got != want {
+		t.Errorf("MaxSeverity2y = %d, want %d", got, want)
+	}
+	if got, want := s.HasPublished.Get(), true; got != want {
+		t.Errorf("HasPublished = %v, want %v", got, want)
+	}
+}
+
+func TestAdvisoryStats_NoRecent(t *testing.T) {
+	now := time.Now().UTC()
+	advisories := []advisory{
+		{GHSAID: "GHSA-1", Severity: "critical", PublishedAt: now.Add(-3 * 365 * 24 * time.Hour)},
+	}
+	s := advisoryStats(advisories, now.Add(-advisorySeverityLookback))
+	if s.MaxSeverity2y.IsSet() {
+		t.Errorf("MaxSeverity2y is set, want unset")
+	}
+	if got, want := s.HasPublished.Get(), true; got != want {
+		t.Errorf("HasPublished = %v, want %v", got, want)
+	}
+
+	s = advisoryStats(nil, now.Add(-advisorySeverityLookback))
+	if got, want := s.HasPublished.Get(), false; got != want {
+		t.Errorf("HasPublished = %v, want %v", got, want)
+	}
+}
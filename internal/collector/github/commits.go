@@ -0,0 +1,148 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/hasura/go-graphql-client"
+
+	"github.com/ossf/criticality_score/v2/internal/githubapi"
+	"github.com/ossf/criticality_score/v2/internal/githubapi/pagination"
+)
+
+const (
+	commitsPerPage = 100
+
+	// maxHistoryCommits limits the number of commits fetched from the history
+	// of a repository's default branch. Only the most recent commits are used
+	// for very active repositories.
+	maxHistoryCommits = 10000
+)
+
+// commit is a single commit on the default branch of a repository.
+type commit struct {
+	AuthoredDate  time.Time
+	CommittedDate time.Time
+	Author        struct {
+		Email string
+		User  struct{ Login string }
+	}
+}
+
+// authorID returns a string identifying the author of the commit.
+//
+// The GitHub login is used if the author's email address is associated with a
+// user, otherwise the lowercased email address is used.
+func (c *commit) authorID() string {
+	if c.Author.User.Login != "" {
+		return c.Author.User.Login
+	}
+	return strings.ToLower(c.Author.Email)
+}
+
+// isBot returns true if the commit was authored by a bot account.
+func (c *commit) isBot() bool {
+	return strings.HasSuffix(c.Author.User.Login, "[bot]") || strings.Contains(c.Author.Email, "[bot]")
+}
+
+type commitHistoryQuery struct {
+	Repository struct {
+		DefaultBranchRef struct {
+			Target struct {
+				Commit struct {
+					History struct {
+						Nodes    []commit
+						PageInfo struct {
+							EndCursor   string
+							HasNextPage bool
+						}
+						TotalCount int
+					} `graphql:"history(since: $since, first: $perPage, after: $endCursor)"`
+				} `graphql:"... on Commit"`
+			}
+		}
+	} `graphql:"repository(owner: $repositoryOwner, name: $repositoryName)"`
+}
+
+// Reset implements the pagination.PagedQuery interface.
+func (q *commitHistoryQuery) Reset() {
+	q.Repository.DefaultBranchRef.Target.Commit.History.Nodes = nil
+}
+
+// Total implements the pagination.PagedQuery interface.
+func (q *commitHistoryQuery) Total() int {
+	return q.Repository.DefaultBranchRef.Target.Commit.History.TotalCount
+}
+
+// Length implements the pagination.PagedQuery interface.
+func (q *commitHistoryQuery) Length() int {
+	return len(q.Repository.DefaultBranchRef.Target.Commit.History.Nodes)
+}
+
+// Get implements the pagination.PagedQuery interface.
+func (q *commitHistoryQuery) Get(i int) any {
+	return q.Repository.DefaultBranchRef.Target.Commit.History.Nodes[i]
+}
+
+// HasNextPage implements the pagination.PagedQuery interface.
+func (q *commitHistoryQuery) HasNextPage() bool {
+	return q.Repository.DefaultBranchRef.Target.Commit.History.PageInfo.HasNextPage
+}
+
+// NextPageVars implements the pagination.PagedQuery interface.
+func (q *commitHistoryQuery) NextPageVars() map[string]any {
+	cursor := q.Repository.DefaultBranchRef.Target.Commit.History.PageInfo.EndCursor
+	if cursor == "" {
+		return map[string]any{
+			"endCursor": (*graphql.String)(nil),
+		}
+	}
+	return map[string]any{
+		"endCursor": graphql.String(cursor),
+	}
+}
+
+// fetchCommitHistory returns the commits on the default branch of the
+// repository since the given time, most recent first.
+//
+// At most maxHistoryCommits commits are returned.
+func fetchCommitHistory(ctx context.Context, c *githubapi.Client, owner, name string, since time.Time) ([]commit, error) {
+	vars := map[string]any{
+		"perPage":         graphql.Int(commitsPerPage),
+		"repositoryOwner": graphql.String(owner),
+		"repositoryName":  graphql.String(name),
+		"since":           githubapi.GitTimestamp{Time: since},
+	}
+	cursor, err := pagination.Query(ctx, c.GraphQL(), &commitHistoryQuery{}, vars)
+	if err != nil {
+		return nil, err
+	}
+	var commits []commit
+	for len(commits) < maxHistoryCommits {
+		obj, err := cursor.Next()
+		if obj == nil && errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		commits = append(commits, obj.(commit))
+	}
+	return commits, nil
+}
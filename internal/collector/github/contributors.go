@@ -0,0 +1,227 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ossf/criticality_score/v2/internal/collector/emaildomain"
+	"github.com/ossf/criticality_score/v2/internal/collector/github/legacy"
+	"github.com/ossf/criticality_score/v2/internal/collector/projectrepo"
+	"github.com/ossf/criticality_score/v2/internal/collector/signal"
+)
+
+const (
+	// DefaultContributorsLookback is the default period of commit history used
+	// for contributor signals.
+	DefaultContributorsLookback = 365 * 24 * time.Hour
+
+	activeMaintainerPeriod = 90 * 24 * time.Hour
+	quarter                = 365 * 24 * time.Hour / 4
+
+	// topOrgsLimit is the number of organizations included in TopOrgs.
+	topOrgsLimit = 5
+)
+
+type contributorsSet struct {
+	// BusFactor is the minimum number of authors that together account for
+	// at least half of the commits in the lookback period.
+	BusFactor signal.Field[int] `desc:"Minimum number of authors that together account for half of the commits." unit:"authors" lookback:"365 days by default" source:"GitHub GraphQL API"`
+
+	// TopContributorShare is the fraction of commits in the lookback period
+	// authored by the most active author.
+	TopContributorShare signal.Field[float64] `desc:"Fraction of commits authored by the most active author." unit:"ratio" lookback:"365 days by default" source:"GitHub GraphQL API"`
+
+	// ActiveMaintainers is the number of authors with at least one commit in
+	// the last 90 days.
+	ActiveMaintainers signal.Field[int] `signal:"active_maintainers_90d" desc:"Number of authors with at least one commit." unit:"authors" lookback:"90 days" source:"GitHub GraphQL API"`
+
+	// NewPerQuarter is the average number of authors per quarter whose first
+	// commit in the lookback period came after its first quarter.
+	NewPerQuarter signal.Field[float64] `signal:"new_per_quarter" desc:"Average number of new authors per quarter." unit:"authors" lookback:"365 days by default" source:"GitHub GraphQL API"`
+
+	// OrgCount is the number of organizations, identified by the domain of
+	// the author's email address, that authored commits in the lookback
+	// period. Webmail domains are ignored.
+	OrgCount signal.Field[int] `desc:"Number of organizations, by email domain, that authored commits." unit:"count" lookback:"365 days by default" source:"GitHub GraphQL API"`
+
+	// TopOrgShare is the fraction of the commits attributed to an
+	// organization that were authored by the most active organization.
+	TopOrgShare signal.Field[float64] `desc:"Fraction of commits attributed to an organization that were authored by the most active one." unit:"ratio" lookback:"365 days by default" source:"GitHub GraphQL API"`
+
+	// TopOrgs is the number of commits authored by each of the most active
+	// organizations.
+	TopOrgs signal.Detail[map[string]int] `desc:"Number of commits authored by each of the most active organizations." unit:"commits" lookback:"365 days by default" source:"GitHub GraphQL API"`
+}
+
+func (s *contributorsSet) Namespace() signal.Namespace {
+	return signal.Namespace("contributors")
+}
+
+// ContributorsSource collects signals about the authors of commits to the
+// default branch of a GitHub repository. Commits by bots are ignored.
+type ContributorsSource struct {
+	aliases  emaildomain.Aliases
+	lookback time.Duration
+}
+
+// NewContributorsSource creates a new ContributorsSource that uses the commit
+// history for the lookback period.
+//
+// The aliases are applied to the domains of the authors' email addresses
+// before they are attributed to an organization.
+func NewContributorsSource(lookback time.Duration, aliases emaildomain.Aliases) signal.Source {
+	return &ContributorsSource{
+		aliases:  aliases,
+		lookback: lookback,
+	}
+}
+
+func (cs *ContributorsSource) EmptySet() signal.Set {
+	return &contributorsSet{}
+}
+
+func (cs *ContributorsSource) IsSupported(r projectrepo.Repo) bool {
+	_, ok := r.(*repo)
+	return ok
+}
+
+func (cs *ContributorsSource) Get(ctx context.Context, r projectrepo.Repo, _ string) (signal.Set, error) {
+	ghr, ok := r.(*repo)
+	if !ok {
+		return nil, errors.New("project is not a github project")
+	}
+	now := time.Now().UTC()
+	since := now.Add(-cs.lookback)
+
+	ghr.logger.Debug("Fetching commit history")
+	commits, err := fetchCommitHistory(ctx, ghr.client, ghr.owner(), ghr.name(), since)
+	if err != nil {
+		return nil, fmt.Errorf("fetch commit history: %w", err)
+	}
+	if len(commits) == maxHistoryCommits {
+		// The history was truncated, so only the period covered by the
+		// commits fetched can be used.
+		since = commits[len(commits)-1].CommittedDate
+	}
+	s := contributorStats(commits, since, now)
+	setOrgStats(s, commits, cs.aliases)
+	return s, nil
+}
+
+// contributorStats calculates the contributor signals for the commits made
+// between since and now.
+func contributorStats(commits []commit, since, now time.Time) *contributorsSet {
+	counts := make(map[string]int)
+	first := make(map[string]time.Time)
+	active := make(map[string]bool)
+	total := 0
+	for i := range commits {
+		c := &commits[i]
+		if c.isBot() {
+			continue
+		}
+		id := c.authorID()
+		if id == "" {
+			continue
+		}
+		total++
+		counts[id]++
+		if t, ok := first[id]; !ok || c.AuthoredDate.Before(t) {
+			first[id] = c.AuthoredDate
+		}
+		if c.AuthoredDate.After(now.Add(-activeMaintainerPeriod)) {
+			active[id] = true
+		}
+	}
+
+	s := &contributorsSet{}
+	s.ActiveMaintainers.Set(len(active))
+
+	sorted := make([]int, 0, len(counts))
+	for _, n := range counts {
+		sorted = append(sorted, n)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(sorted)))
+	busFactor := 0
+	for covered := 0; covered*2 < total; busFactor++ {
+		covered += sorted[busFactor]
+	}
+	s.BusFactor.Set(busFactor)
+	if total > 0 {
+		s.TopContributorShare.Set(legacy.Round(float64(sorted[0])/float64(total), 2))
+	}
+
+	// Authors seen in the first quarter are treated as existing contributors,
+	// so the period must be longer than a quarter to find new ones.
+	if period := now.Sub(since); period > quarter {
+		baseline := since.Add(quarter)
+		newCount := 0
+		for _, t := range first {
+			if t.After(baseline) {
+				newCount++
+			}
+		}
+		quarters := float64(period-quarter) / float64(quarter)
+		s.NewPerQuarter.Set(legacy.Round(float64(newCount)/quarters, 2))
+	}
+	return s
+}
+
+// setOrgStats sets the organization signals in s for the commits, using the
+// domain of each author's email address to identify their organization.
+func setOrgStats(s *contributorsSet, commits []commit, aliases emaildomain.Aliases) {
+	counts := make(map[string]int)
+	total := 0
+	for i := range commits {
+		c := &commits[i]
+		if c.isBot() {
+			continue
+		}
+		org := aliases.Org(c.Author.Email)
+		if org == "" {
+			continue
+		}
+		total++
+		counts[org]++
+	}
+	s.OrgCount.Set(len(counts))
+	if total == 0 {
+		return
+	}
+
+	orgs := make([]string, 0, len(counts))
+	for org := range counts {
+		orgs = append(orgs, org)
+	}
+	// Sort by the number of commits, breaking ties by name so the top
+	// organizations are stable.
+	sort.Slice(orgs, func(i, j int) bool {
+		if counts[orgs[i]] != counts[orgs[j]] {
+			return counts[orgs[i]] > counts[orgs[j]]
+		}
+		return orgs[i] < orgs[j]
+	})
+	s.TopOrgShare.Set(legacy.Round(float64(counts[orgs[0]])/float64(total), 2))
+	top := make(map[string]int)
+	for _, org := range orgs[:min(len(orgs), topOrgsLimit)] {
+		top[org] = counts[org]
+	}
+	s.TopOrgs.Set(top)
+}
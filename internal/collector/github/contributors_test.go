@@ -0,0 +1,214 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ossf/criticality_score/v2/internal/collector/emaildomain"
+	"github.com/ossf/criticality_score/v2/internal/collector/signal"
+)
+
+func TestContributorStats(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	//nolint:govet
+	tests := []struct {
+		name    string
+		commits string
+		since   time.Time
+		want    *contributorsSet
+	}{
+		{
+			name: "authors",
+			commits: `[
+				{"authoredDate": "2023-12-20T00:00:00Z", "author": {"email": "support@github.com", "user": {"login": "dependabot[bot]"}}},
+				{"authoredDate": "2023-12-20T00:00:00Z", "author": {"email": "49699333+dependabot[bot]@users.noreply.github.com", "user": null}},
+				{"authoredDate": "2023-12-01T00:00:00Z", "author": {"email": "bob@example.com", "user": {"login": "bob"}}},
+				{"authoredDate": "2023-11-01T00:00:00Z", "author": {"email": "dave@example.com", "user": null}},
+				{"authoredDate": "2023-08-01T00:00:00Z", "author": {"email": "carol@example.com", "user": null}},
+				{"authoredDate": "2023-06-01T00:00:00Z", "author": {"email": "Carol@Example.com", "user": null}},
+				{"authoredDate": "2023-03-01T00:00:00Z", "author": {"email": "bob@example.com", "user": {"login": "bob"}}},
+				{"authoredDate": "2023-02-03T00:00:00Z", "author": {"email": "alice@example.com", "user": {"login": "alice"}}},
+				{"authoredDate": "2023-02-02T00:00:00Z", "author": {"email": "alice@example.com", "user": {"login": "alice"}}},
+				{"authoredDate": "2023-02-01T00:00:00Z", "author": {"email": "alice@example.com", "user": {"login": "alice"}}}
+			]`,
+			since: now.Add(-DefaultContributorsLookback),
+			want: &contributorsSet{
+				BusFactor:           signal.Val(2),
+				TopContributorShare: signal.Val(0.38),
+				ActiveMaintainers:   signal.Val(2),
+				NewPerQuarter:       signal.Val(0.67),
+			},
+		},
+		{
+			name: "only bots",
+			commits: `[
+				{"authoredDate": "2023-12-20T00:00:00Z", "author": {"email": "support@github.com", "user": {"login": "dependabot[bot]"}}}
+			]`,
+			since: now.Add(-DefaultContributorsLookback),
+			want: &contributorsSet{
+				BusFactor:         signal.Val(0),
+				ActiveMaintainers: signal.Val(0),
+				NewPerQuarter:     signal.Val(0.0),
+			},
+		},
+		{
+			name:    "no commits in short period",
+			commits: `[]`,
+			since:   now.Add(-30 * 24 * time.Hour),
+			want: &contributorsSet{
+				BusFactor:         signal.Val(0),
+				ActiveMaintainers: signal.Val(0),
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			commits := decodeNodes[commit](t, test.commits)
+			got := contributorStats(commits, test.since, now)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("contributorStats() = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSetOrgStats(t *testing.T) {
+	//nolint:govet
+	tests := []struct {
+		name         string
+		emails       []string
+		aliases      emaildomain.Aliases
+		wantOrgCount signal.Field[int]
+		wantTopShare signal.Field[float64]
+		wantTopOrgs  map[string]int
+	}{
+		{
+			name: "orgs",
+			emails: []string{
+				"alice@example.com",
+				"alice@example.com",
+				"alice@example.com",
+				"alice@example.com",
+				"bob@Chromium.org",
+				"bob@google.com",
+				"carol@gmail.com",
+				"dave@users.noreply.github.com",
+				"erin@a.example.org",
+				"frank@b.example.org",
+				"grace@c.example.org",
+				"heidi@d.example.org",
+			},
+			aliases:      emaildomain.Aliases{"chromium.org": "google.com"},
+			wantOrgCount: signal.Val(6),
+			wantTopShare: signal.Val(0.4),
+			wantTopOrgs: map[string]int{
+				"example.com":   4,
+				"google.com":    2,
+				"a.example.org": 1,
+				"b.example.org": 1,
+				"c.example.org": 1,
+			},
+		},
+		{
+			name:         "webmail only",
+			emails:       []string{"alice@gmail.com"},
+			wantOrgCount: signal.Val(0),
+		},
+		{
+			name:         "no commits",
+			wantOrgCount: signal.Val(0),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var commits []commit
+			for _, email := range test.emails {
+				c := commit{}
+				c.Author.Email = email
+				commits = append(commits, c)
+			}
+			s := &contributorsSet{}
+			setOrgStats(s, commits, test.aliases)
+			if !reflect.DeepEqual(s.OrgCount, test.wantOrgCount) {
+				t.Errorf("OrgCount = %+v, want %+v", s.OrgCount, test.wantOrgCount)
+			}
+			if !reflect.DeepEqual(s.TopOrgShare, test.wantTopShare) {
+				t.Errorf("TopOrgShare = %+v, want %+v", s.TopOrgShare, test.wantTopShare)
+			}
+			if got := s.TopOrgs.Get(); !reflect.DeepEqual(got, test.wantTopOrgs) {
+				t.Errorf("TopOrgs = %v, want %v", got, test.wantTopOrgs)
+			}
+		})
+	}
+}
+
+func TestContributorsSource_Get(t *testing.T) {
+	recent := time.Now().UTC().Add(-time.Hour).Format(time.RFC3339)
+	var nodes []string
+	for i := 0; i < commitsPerPage; i++ {
+		nodes = append(nodes, fmt.Sprintf(`{"authoredDate": %q, "committedDate": %q, "author": {"email": "user%d@example.com", "user": {"login": "user%d"}}}`, recent, recent, i%10, i%10))
+	}
+	page := fmt.Sprintf(`{"repository": {"defaultBranchRef": {"target": {"history": {
+		"nodes": [%s],
+		"pageInfo": {"endCursor": "next", "hasNextPage": true},
+		"totalCount": 50000
+	}}}}}`, strings.Join(nodes, ","))
+	f := newFakeGraphQL(t, func(graphQLRequest) string { return page })
+
+	cs := NewContributorsSource(DefaultContributorsLookback, nil)
+	s, err := cs.Get(context.Background(), f.repo(t), "")
+	if err != nil {
+		t.Fatalf("Get() = %v, want no error", err)
+	}
+
+	reqs := f.queries("history(since: $since, first: $perPage, after: $endCursor)")
+	if got, want := len(reqs), maxHistoryCommits/commitsPerPage; got != want {
+		t.Fatalf("history requests = %d, want %d", got, want)
+	}
+	vars := reqs[0].Variables
+	if vars["repositoryOwner"] != "owner" || vars["repositoryName"] != "repo" {
+		t.Errorf("repository = %v/%v, want owner/repo", vars["repositoryOwner"], vars["repositoryName"])
+	}
+	if got, want := vars["perPage"], float64(commitsPerPage); got != want {
+		t.Errorf("perPage = %v, want %v", got, want)
+	}
+	since, err := time.Parse(time.RFC3339, fmt.Sprint(vars["since"]))
+	if err != nil {
+		t.Fatalf("Parse(since) = %v, want no error", err)
+	}
+	if d := time.Since(since) - DefaultContributorsLookback; d < 0 || d > time.Minute {
+		t.Errorf("since = %v, want %v ago", since, DefaultContributorsLookback)
+	}
+	if got := vars["endCursor"]; got != nil {
+		t.Errorf("first endCursor = %v, want nil", got)
+	}
+	if got, want := reqs[1].Variables["endCursor"], "next"; got != want {
+		t.Errorf("second endCursor = %v, want %v", got, want)
+	}
+
+	cset := s.(*contributorsSet)
+	if got, want := cset.ActiveMaintainers.Get(), 10; got != want {
+		t.Errorf("ActiveMaintainers = %d, want %d", got, want)
+	}
+	if got, want := cset.BusFactor.Get(), 5; got != want {
+		t.Errorf("BusFactor = %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ossf/criticality_score/v2/internal/collector/projectrepo"
+	"github.com/ossf/criticality_score/v2/internal/collector/signal"
+	"github.com/ossf/criticality_score/v2/internal/githubapi"
+)
+
+type hygieneSet struct {
+	// HasSecurityPolicy is true if GitHub recognizes a security policy for
+	// the repository, either a SECURITY.md file in the repository or one
+	// inherited from the owner's .github repository.
+	HasSecurityPolicy signal.Field[bool] `desc:"Whether GitHub recognizes a security policy for the repository." source:"GitHub GraphQL API"`
+
+	// HasPrivateVulnerabilityReporting is true if security issues can be
+	// privately reported through GitHub.
+	HasPrivateVulnerabilityReporting signal.Field[bool] `desc:"Whether security issues can be privately reported through GitHub." source:"GitHub REST API"`
+
+	// HasCodeowners is true if the repository contains a CODEOWNERS file.
+	HasCodeowners signal.Field[bool] `desc:"Whether the repository contains a CODEOWNERS file." source:"GitHub GraphQL API"`
+
+	// HasFunding is true if the repository contains a FUNDING.yml file.
+	HasFunding signal.Field[bool] `desc:"Whether the repository contains a FUNDING.yml file." source:"GitHub GraphQL API"`
+
+	// HasCIWorkflows is true if the repository contains a GitHub Actions
+	// workflows directory.
+	HasCIWorkflows signal.Field[bool] `signal:"has_ci_workflows" desc:"Whether the repository contains GitHub Actions workflows." source:"GitHub GraphQL API"`
+
+	// LatestTagSigned is true if the most recent tag is an annotated tag with
+	// a signature that GitHub was able to verify.
+	LatestTagSigned signal.Field[bool] `desc:"Whether the most recent tag has a valid signature." source:"GitHub GraphQL API"`
+
+	// HasBranchProtection is true if the default branch has rules that
+	// restrict how it can be updated.
+	HasBranchProtection signal.Field[bool] `desc:"Whether the default branch has protection rules." source:"GitHub GraphQL API"`
+}
+
+func (s *hygieneSet) Namespace() signal.Namespace {
+	return signal.Namespace("hygiene")
+}
+
+// gitObject is present if the object exists in the repository.
+type gitObject *struct{ Oid string }
+
+// tagTarget is used to find the signature of an annotated tag.
+type tagTarget struct {
+	Signature *struct{ IsValid bool }
+}
+
+// hygieneData contains the fields of a repository used for hygiene signals.
+//
+// It is fetched with githubapi.BatchQuery, which decodes the result with the
+// encoding/json package. Inline fragments must be embedded so that their
+// fields are promoted.
+type hygieneData struct {
+	IsSecurityPolicyEnabled bool
+
+	RootCodeowners   gitObject `graphql:"rootcodeowners:object(expression: \"HEAD:CODEOWNERS\")"`
+	GitHubCodeowners gitObject `graphql:"githubcodeowners:object(expression: \"HEAD:.github/CODEOWNERS\")"`
+	DocsCodeowners   gitObject `graphql:"docscodeowners:object(expression: \"HEAD:docs/CODEOWNERS\")"`
+	Funding          gitObject `graphql:"funding:object(expression: \"HEAD:.github/FUNDING.yml\")"`
+	Workflows        gitObject `graphql:"workflows:object(expression: \"HEAD:.github/workflows\")"`
+
+	DefaultBranchRef *struct {
+		RefUpdateRule *struct{ AllowsDeletions bool }
+	}
+
+	Tags struct {
+		Nodes []struct {
+			Target struct {
+				tagTarget `graphql:"... on Tag"`
+			}
+		}
+	} `graphql:"tags:refs(refPrefix: \"refs/tags/\", first: 1, orderBy: {field: TAG_COMMIT_DATE, direction: DESC})"`
+}
+
+// HygieneSource collects signals about the presence of files and settings
+// that indicate a GitHub repository follows good project hygiene.
+type HygieneSource struct{}
+
+func (hs *HygieneSource) EmptySet() signal.Set {
+	return &hygieneSet{}
+}
+
+func (hs *HygieneSource) IsSupported(r projectrepo.Repo) bool {
+	_, ok := r.(*repo)
+	return ok
+}
+
+func (hs *HygieneSource) Get(ctx context.Context, r projectrepo.Repo, _ string) (signal.Set, error) {
+	ghr, ok := r.(*repo)
+	if !ok {
+		return nil, errors.New("project is not a github project")
+	}
+
+	ghr.logger.Debug("Fetching hygiene data")
+	query := fmt.Sprintf("repository(owner: %q, name: %q)", ghr.owner(), ghr.name())
+	res, err := githubapi.BatchQuery[hygieneData](ctx, ghr.client, map[string]string{"repo": query})
+	if err != nil {
+		return nil, fmt.Errorf("fetch hygiene data: %w", err)
+	}
+	s := hygieneSignals(res["repo"])
+
+	// Private vulnerability reporting is not available with GraphQL.
+	ghr.logger.Debug("Fetching private vulnerability reporting status")
+	enabled, err := fetchPrivateVulnerabilityReporting(ctx, ghr.client, ghr.owner(), ghr.name())
+	switch c := githubapi.ErrorResponseStatusCode(err); {
+	case c == http.StatusForbidden || c == http.StatusNotFound:
+		// The status is not visible to the client, so leave it unset.
+	case err != nil:
+		return nil, fmt.Errorf("fetch private vulnerability reporting: %w", err)
+	default:
+		s.HasPrivateVulnerabilityReporting.Set(enabled)
+	}
+	return s, nil
+}
+
+// hygieneSignals returns the signals present in data.
+func hygieneSignals(data hygieneData) *hygieneSet {
+	s := &hygieneSet{
+		HasSecurityPolicy: signal.Val(data.IsSecurityPolicyEnabled),
+		HasCodeowners:     signal.Val(data.RootCodeowners != nil || data.GitHubCodeowners != nil || data.DocsCodeowners != nil),
+		HasFunding:        signal.Val(data.Funding != nil),
+		HasCIWorkflows:    signal.Val(data.Workflows != nil),
+	}
+	if data.DefaultBranchRef != nil {
+		s.HasBranchProtection.Set(data.DefaultBranchRef.RefUpdateRule != nil)
+	}
+	if len(data.Tags.Nodes) > 0 {
+		sig := data.Tags.Nodes[0].Target.Signature
+		s.LatestTagSigned.Set(sig != nil && sig.IsValid)
+	}
+	return s
+}
+
+// fetchPrivateVulnerabilityReporting returns whether private vulnerability
+// reporting is enabled for the repository.
+func fetchPrivateVulnerabilityReporting(ctx context.Context, c *githubapi.Client, owner, name string) (bool, error) {
+	req, err := c.Rest().NewRequest(http.MethodGet, fmt.Sprintf("repos/%s/%s/private-vulnerability-reporting", owner, name), nil)
+	if err != nil {
+		return false, err
+	}
+	var v struct {
+		Enabled bool `json:"enabled"`
+	}
+	if _, err := c.Rest().Do(ctx, req, &v); err != nil {
+		return false, err
+	}
+	return v.Enabled, nil
+}
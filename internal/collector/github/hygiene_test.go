@@ -0,0 +1,189 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/ossf/criticality_score/v2/internal/collector/signal"
+)
+
+func TestHygieneSignals(t *testing.T) {
+	//nolint:govet
+	tests := []struct {
+		name      string
+		data      string
+		want      map[string]bool
+		wantUnset []string
+	}{
+		{
+			name: "all present",
+			data: `{
+				"isSecurityPolicyEnabled": true,
+				"rootcodeowners": null,
+				"githubcodeowners": {"oid": "abc"},
+				"docscodeowners": null,
+				"funding": {"oid": "def"},
+				"workflows": {"oid": "123"},
+				"defaultBranchRef": {"refUpdateRule": {"allowsDeletions": false}},
+				"tags": {"nodes": [{"target": {"signature": {"isValid": true}}}]}
+			}`,
+			want: map[string]bool{
+				"HasSecurityPolicy":   true,
+				"HasCodeowners":       true,
+				"HasFunding":          true,
+				"HasCIWorkflows":      true,
+				"LatestTagSigned":     true,
+				"HasBranchProtection": true,
+			},
+		},
+		{
+			name: "none present",
+			data: `{
+				"isSecurityPolicyEnabled": false,
+				"rootcodeowners": null,
+				"githubcodeowners": null,
+				"docscodeowners": null,
+				"funding": null,
+				"workflows": null,
+				"defaultBranchRef": {"refUpdateRule": null},
+				"tags": {"nodes": [{"target": {}}]}
+			}`,
+			want: map[string]bool{
+				"HasSecurityPolicy":   false,
+				"HasCodeowners":       false,
+				"HasFunding":          false,
+				"HasCIWorkflows":      false,
+				"LatestTagSigned":     false,
+				"HasBranchProtection": false,
+			},
+		},
+		{
+			name: "invalid tag signature",
+			data: `{"tags": {"nodes": [{"target": {"signature": {"isValid": false}}}]}}`,
+			want: map[string]bool{
+				"LatestTagSigned": false,
+			},
+		},
+		{
+			name: "empty repository",
+			data: `{"defaultBranchRef": null, "tags": {"nodes": []}}`,
+			want: map[string]bool{
+				"HasCodeowners": false,
+			},
+			wantUnset: []string{"LatestTagSigned", "HasBranchProtection"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var data hygieneData
+			if err := json.Unmarshal([]byte(test.data), &data); err != nil {
+				t.Fatalf("Unmarshal() = %v, want no error", err)
+			}
+			s := hygieneSignals(data)
+			fields := map[string]interface {
+				Get() bool
+				IsSet() bool
+			}{
+				"HasSecurityPolicy":   &s.HasSecurityPolicy,
+				"HasCodeowners":       &s.HasCodeowners,
+				"HasFunding":          &s.HasFunding,
+				"HasCIWorkflows":      &s.HasCIWorkflows,
+				"LatestTagSigned":     &s.LatestTagSigned,
+				"HasBranchProtection": &s.HasBranchProtection,
+			}
+			for name, want := range test.want {
+				if got := fields[name].Get(); got != want {
+					t.Errorf("%s = %v, want %v", name, got, want)
+				}
+			}
+			for _, name := range test.wantUnset {
+				if fields[name].IsSet() {
+					t.Errorf("%s is set, want unset", name)
+				}
+			}
+		})
+	}
+}
+
+func TestHygieneSource_Get(t *testing.T) {
+	//nolint:govet
+	tests := []struct {
+		name string
+		data string
+		rest map[string]string
+		want *hygieneSet
+	}{
+		{
+			name: "reporting enabled",
+			data: `{
+				"isSecurityPolicyEnabled": true,
+				"rootcodeowners": {"oid": "abc"},
+				"githubcodeowners": null,
+				"docscodeowners": null,
+				"funding": null,
+				"workflows": {"oid": "123"},
+				"defaultBranchRef": {"refUpdateRule": null},
+				"tags": {"nodes": [{"target": {"signature": {"isValid": true}}}]}
+			}`,
+			rest: map[string]string{
+				"/repos/owner/repo/private-vulnerability-reporting": `{"enabled": true}`,
+			},
+			want: &hygieneSet{
+				HasSecurityPolicy:                signal.Val(true),
+				HasPrivateVulnerabilityReporting: signal.Val(true),
+				HasCodeowners:                    signal.Val(true),
+				HasFunding:                       signal.Val(false),
+				HasCIWorkflows:                   signal.Val(true),
+				LatestTagSigned:                  signal.Val(true),
+				HasBranchProtection:              signal.Val(false),
+			},
+		},
+		{
+			name: "reporting not visible",
+			data: `{"defaultBranchRef": null, "tags": {"nodes": []}}`,
+			want: &hygieneSet{
+				HasSecurityPolicy: signal.Val(false),
+				HasCodeowners:     signal.Val(false),
+				HasFunding:        signal.Val(false),
+				HasCIWorkflows:    signal.Val(false),
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f := newFakeGraphQL(t, func(graphQLRequest) string {
+				return `{"field0": ` + test.data + `}`
+			})
+			f.rest = test.rest
+
+			s, err := (&HygieneSource{}).Get(context.Background(), f.repo(t), "")
+			if err != nil {
+				t.Fatalf("Get() = %v, want no error", err)
+			}
+
+			reqs := f.queries(`field0:repository(owner: "owner", name: "repo")`)
+			if got := len(reqs); got != 1 {
+				t.Fatalf("hygiene requests = %d, want 1", got)
+			}
+			if !reflect.DeepEqual(s, test.want) {
+				t.Errorf("Get() = %+v, want %+v", s, test.want)
+			}
+		})
+	}
+}
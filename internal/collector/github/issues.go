@@ -0,0 +1,190 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/hasura/go-graphql-client"
+
+	"github.com/ossf/criticality_score/v2/internal/collector/github/legacy"
+	"github.com/ossf/criticality_score/v2/internal/collector/signal"
+	"github.com/ossf/criticality_score/v2/internal/githubapi"
+	"github.com/ossf/criticality_score/v2/internal/githubapi/pagination"
+)
+
+const issuesPerPage = 50
+
+// issue is a single issue for a repository.
+type issue struct {
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	ClosedAt  *time.Time
+	Author    struct{ Login string }
+	Comments  struct {
+		Nodes []struct {
+			CreatedAt         time.Time
+			AuthorAssociation string
+			Author            struct{ Login string }
+		}
+	} `graphql:"comments(first: 10)"`
+}
+
+// firstMaintainerComment returns the time of the first comment on the issue
+// by an owner, member or collaborator of the repository, other than the
+// author of the issue.
+func (i *issue) firstMaintainerComment() (time.Time, bool) {
+	for _, c := range i.Comments.Nodes {
+		if c.Author.Login == i.Author.Login {
+			continue
+		}
+		switch c.AuthorAssociation {
+		case "OWNER", "MEMBER", "COLLABORATOR":
+			return c.CreatedAt, true
+		}
+	}
+	return time.Time{}, false
+}
+
+type issuesQuery struct {
+	Repository struct {
+		Issues struct {
+			Nodes    []issue
+			PageInfo struct {
+				EndCursor   string
+				HasNextPage bool
+			}
+			TotalCount int
+		} `graphql:"issues(first: $perPage, after: $endCursor, orderBy: {field: UPDATED_AT, direction: DESC})"`
+	} `graphql:"repository(owner: $repositoryOwner, name: $repositoryName)"`
+}
+
+// Reset implements the pagination.PagedQuery interface.
+func (q *issuesQuery) Reset() {
+	q.Repository.Issues.Nodes = nil
+}
+
+// Total implements the pagination.PagedQuery interface.
+func (q *issuesQuery) Total() int {
+	return q.Repository.Issues.TotalCount
+}
+
+// Length implements the pagination.PagedQuery interface.
+func (q *issuesQuery) Length() int {
+	return len(q.Repository.Issues.Nodes)
+}
+
+// Get implements the pagination.PagedQuery interface.
+func (q *issuesQuery) Get(i int) any {
+	return q.Repository.Issues.Nodes[i]
+}
+
+// HasNextPage implements the pagination.PagedQuery interface.
+func (q *issuesQuery) HasNextPage() bool {
+	return q.Repository.Issues.PageInfo.HasNextPage
+}
+
+// NextPageVars implements the pagination.PagedQuery interface.
+func (q *issuesQuery) NextPageVars() map[string]any {
+	cursor := q.Repository.Issues.PageInfo.EndCursor
+	if cursor == "" {
+		return map[string]any{
+			"endCursor": (*graphql.String)(nil),
+		}
+	}
+	return map[string]any{
+		"endCursor": graphql.String(cursor),
+	}
+}
+
+// fetchIssues returns the issues for the repository that have been updated
+// since the given time, most recently updated first.
+//
+// At most legacy.MaxIssuesLimit issues are returned.
+func fetchIssues(ctx context.Context, c *githubapi.Client, owner, name string, since time.Time) ([]issue, error) {
+	vars := map[string]any{
+		"perPage":         graphql.Int(issuesPerPage),
+		"repositoryOwner": graphql.String(owner),
+		"repositoryName":  graphql.String(name),
+	}
+	cursor, err := pagination.Query(ctx, c.GraphQL(), &issuesQuery{}, vars)
+	if err != nil {
+		return nil, err
+	}
+	var issues []issue
+	for len(issues) < legacy.MaxIssuesLimit {
+		obj, err := cursor.Next()
+		if obj == nil && errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		i := obj.(issue)
+		if i.UpdatedAt.Before(since) {
+			break
+		}
+		issues = append(issues, i)
+	}
+	return issues, nil
+}
+
+// setIssueTriage sets the issue triage signals in s, by paging through the
+// issues updated during the lookback period.
+func setIssueTriage(ctx context.Context, ghr *repo, s *signal.IssuesSet) error {
+	since := time.Now().UTC().Add(-legacy.IssueLookback)
+
+	ghr.logger.Debug("Fetching issues")
+	issues, err := fetchIssues(ctx, ghr.client, ghr.owner(), ghr.name(), since)
+	if err != nil {
+		return fmt.Errorf("fetch issues: %w", err)
+	}
+	setIssueTriageStats(s, issues, since)
+	s.OpenCount.Set(ghr.BasicData.OpenIssues.TotalCount)
+	return nil
+}
+
+// setIssueTriageStats sets the issue triage signals in s for the issues that
+// were opened or closed since the given time.
+func setIssueTriageStats(s *signal.IssuesSet, issues []issue, since time.Time) {
+	opened := 0
+	closed := 0
+	var hoursToResponse, hoursToClose []float64
+	for n := range issues {
+		i := &issues[n]
+		if i.CreatedAt.After(since) {
+			opened++
+			if t, ok := i.firstMaintainerComment(); ok {
+				hoursToResponse = append(hoursToResponse, t.Sub(i.CreatedAt).Hours())
+			}
+		}
+		if i.ClosedAt != nil && i.ClosedAt.After(since) {
+			closed++
+			hoursToClose = append(hoursToClose, i.ClosedAt.Sub(i.CreatedAt).Hours())
+		}
+	}
+	if opened > 0 {
+		s.ClosedToOpenedRatio.Set(legacy.Round(float64(closed)/float64(opened), 2))
+	}
+	if len(hoursToResponse) > 0 {
+		s.MedianHoursToFirstResponse.Set(legacy.Round(legacy.Median(hoursToResponse), 2))
+	}
+	if len(hoursToClose) > 0 {
+		s.MedianHoursToClose.Set(legacy.Round(legacy.Median(hoursToClose), 2))
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ossf/criticality_score/v2/internal/collector/github/legacy"
+	"github.com/ossf/criticality_score/v2/internal/collector/signal"
+)
+
+func TestSetIssueTriageStats(t *testing.T) {
+	since := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	//nolint:govet
+	tests := []struct {
+		name   string
+		issues string
+		want   *signal.IssuesSet
+	}{
+		{
+			name: "issues",
+			issues: `[
+				{"createdAt": "2023-06-14T00:00:00Z", "closedAt": null, "author": {"login": "grace"}, "comments": {"nodes": []}},
+				{"createdAt": "2023-06-13T00:00:00Z", "closedAt": null, "author": {"login": "frank"}, "comments": {"nodes": []}},
+				{"createdAt": "2023-06-11T00:00:00Z", "closedAt": null, "author": {"login": "dave"}, "comments": {"nodes": [
+					{"createdAt": "2023-06-11T01:00:00Z", "authorAssociation": "OWNER", "author": {"login": "dave"}},
+					{"createdAt": "2023-06-12T06:00:00Z", "authorAssociation": "COLLABORATOR", "author": {"login": "erin"}}
+				]}},
+				{"createdAt": "2023-06-10T00:00:00Z", "closedAt": "2023-06-12T00:00:00Z", "author": {"login": "alice"}, "comments": {"nodes": [
+					{"createdAt": "2023-06-10T01:00:00Z", "authorAssociation": "NONE", "author": {"login": "bob"}},
+					{"createdAt": "2023-06-10T10:00:00Z", "authorAssociation": "MEMBER", "author": {"login": "carol"}}
+				]}},
+				{"createdAt": "2023-05-01T00:00:00Z", "closedAt": "2023-06-01T12:00:00Z", "author": {"login": "heidi"}, "comments": {"nodes": []}}
+			]`,
+			want: &signal.IssuesSet{
+				MedianHoursToFirstResponse: signal.Val(20.0),
+				MedianHoursToClose:         signal.Val(402.0),
+				ClosedToOpenedRatio:        signal.Val(0.5),
+			},
+		},
+		{
+			name: "no maintainer response",
+			issues: `[
+				{"createdAt": "2023-06-10T00:00:00Z", "closedAt": null, "author": {"login": "alice"}, "comments": {"nodes": [
+					{"createdAt": "2023-06-10T01:00:00Z", "authorAssociation": "NONE", "author": {"login": "bob"}}
+				]}}
+			]`,
+			want: &signal.IssuesSet{
+				ClosedToOpenedRatio: signal.Val(0.0),
+			},
+		},
+		{
+			name:   "no issues",
+			issues: `[]`,
+			want:   &signal.IssuesSet{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			issues := decodeNodes[issue](t, test.issues)
+			got := &signal.IssuesSet{}
+			setIssueTriageStats(got, issues, since)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("setIssueTriageStats() = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestIssuesSource_Get(t *testing.T) {
+	now := time.Now().UTC()
+	issuesPage := func(updated time.Time, hasNextPage bool) string {
+		ts := updated.Format(time.RFC3339)
+		node := fmt.Sprintf(`{"createdAt": %q, "updatedAt": %q, "closedAt": %q, "author": {"login": "alice"}, "comments": {"nodes": []}}`, ts, ts, ts)
+		return fmt.Sprintf(`{"repository": {"issues": {
+			"nodes": [%s],
+			"pageInfo": {"endCursor": "next", "hasNextPage": %t},
+			"totalCount": 100000
+		}}}`, strings.Repeat(node+",", issuesPerPage-1)+node, hasNextPage)
+	}
+	//nolint:govet
+	tests := []struct {
+		name         string
+		triage       bool
+		page         string
+		wantRequests int
+		wantOpen     signal.Field[int]
+		wantRatio    signal.Field[float64]
+	}{
+		{
+			name:         "capped",
+			triage:       true,
+			page:         issuesPage(now.Add(-time.Hour), true),
+			wantRequests: legacy.MaxIssuesLimit / issuesPerPage,
+			wantOpen:     signal.Val(12),
+			wantRatio:    signal.Val(1.0),
+		},
+		{
+			name:         "last page",
+			triage:       true,
+			page:         issuesPage(now.Add(-time.Hour), false),
+			wantRequests: 1,
+			wantOpen:     signal.Val(12),
+			wantRatio:    signal.Val(1.0),
+		},
+		{
+			name:         "updated before lookback",
+			triage:       true,
+			page:         issuesPage(now.Add(-legacy.IssueLookback-time.Hour), true),
+			wantRequests: 1,
+			wantOpen:     signal.Val(12),
+		},
+		{
+			name:         "triage disabled",
+			page:         issuesPage(now.Add(-time.Hour), true),
+			wantRequests: 0,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f := newFakeGraphQL(t, func(graphQLRequest) string { return test.page })
+			f.rest = map[string]string{
+				"/repos/owner/repo/issues":          `[]`,
+				"/repos/owner/repo/issues/comments": `[]`,
+			}
+			r := f.repo(t)
+			r.BasicData.OpenIssues.TotalCount = 12
+
+			s, err := NewIssuesSource(test.triage).Get(context.Background(), r, "")
+			if err != nil {
+				t.Fatalf("Get() = %v, want no error", err)
+			}
+
+			reqs := f.queries("issues(first: $perPage, after: $endCursor, orderBy: {field: UPDATED_AT, direction: DESC})")
+			if got := len(reqs); got != test.wantRequests {
+				t.Fatalf("issue requests = %d, want %d", got, test.wantRequests)
+			}
+			if len(reqs) > 0 {
+				vars := reqs[0].Variables
+				if vars["repositoryOwner"] != "owner" || vars["repositoryName"] != "repo" {
+					t.Errorf("repository = %v/%v, want owner/repo", vars["repositoryOwner"], vars["repositoryName"])
+				}
+				if got, want := vars["perPage"], float64(issuesPerPage); got != want {
+					t.Errorf("perPage = %v, want %v", got, want)
+				}
+			}
+
+			is := s.(*signal.IssuesSet)
+			if got, want := is.UpdatedCount.Get(), 0; !is.UpdatedCount.IsSet() || got != want {
+				t.Errorf("UpdatedCount = %d, want %d", got, want)
+			}
+			if !reflect.DeepEqual(is.OpenCount, test.wantOpen) {
+				t.Errorf("OpenCount = %+v, want %+v", is.OpenCount, test.wantOpen)
+			}
+			if !reflect.DeepEqual(is.ClosedToOpenedRatio, test.wantRatio) {
+				t.Errorf("ClosedToOpenedRatio = %+v, want %+v", is.ClosedToOpenedRatio, test.wantRatio)
+			}
+		})
+	}
+}
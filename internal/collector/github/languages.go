@@ -0,0 +1,135 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hasura/go-graphql-client"
+
+	"github.com/ossf/criticality_score/v2/internal/collector/github/legacy"
+	"github.com/ossf/criticality_score/v2/internal/collector/projectrepo"
+	"github.com/ossf/criticality_score/v2/internal/collector/signal"
+	"github.com/ossf/criticality_score/v2/internal/githubapi"
+)
+
+// nativeLanguages is the set of languages, as named by GitHub, that are
+// compiled to native code.
+var nativeLanguages = map[string]bool{
+	"Assembly":      true,
+	"C":             true,
+	"C++":           true,
+	"Objective-C":   true,
+	"Objective-C++": true,
+	"Rust":          true,
+	"Unix Assembly": true,
+}
+
+type languagesSet struct {
+	// Bytes is the number of bytes of code in the repository for each
+	// language.
+	Bytes signal.Detail[map[string]int64] `desc:"Number of bytes of code in each language." unit:"bytes" source:"GitHub GraphQL API"`
+
+	// TotalBytes is the number of bytes of code in the repository across all
+	// languages.
+	TotalBytes signal.Field[int64] `desc:"Number of bytes of code across all languages." unit:"bytes" source:"GitHub GraphQL API"`
+
+	// HasNativeCode is true if the repository contains code in a language
+	// that is compiled to native code, such as C, C++, Rust or assembly.
+	HasNativeCode signal.Field[bool] `desc:"Whether the repository contains code compiled to native code." source:"GitHub GraphQL API"`
+
+	// NativeShare is the fraction of the bytes of code in the repository
+	// that are in a language that is compiled to native code.
+	NativeShare signal.Field[float64] `desc:"Fraction of the code in languages compiled to native code." unit:"ratio" source:"GitHub GraphQL API"`
+}
+
+func (s *languagesSet) Namespace() signal.Namespace {
+	return signal.Namespace("languages")
+}
+
+type languageData struct {
+	TotalSize int64
+	Edges     []struct {
+		Size int64
+		Node struct{ Name string }
+	}
+}
+
+// LanguagesSource collects the breakdown of the code in a GitHub repository by
+// language.
+type LanguagesSource struct{}
+
+func (ls *LanguagesSource) EmptySet() signal.Set {
+	return &languagesSet{}
+}
+
+func (ls *LanguagesSource) IsSupported(r projectrepo.Repo) bool {
+	_, ok := r.(*repo)
+	return ok
+}
+
+func (ls *LanguagesSource) Get(ctx context.Context, r projectrepo.Repo, _ string) (signal.Set, error) {
+	ghr, ok := r.(*repo)
+	if !ok {
+		return nil, errors.New("project is not a github project")
+	}
+	ghr.logger.Debug("Fetching languages")
+	data, err := fetchLanguages(ctx, ghr.client, ghr.owner(), ghr.name())
+	if err != nil {
+		return nil, fmt.Errorf("fetch languages: %w", err)
+	}
+	return languageStats(data), nil
+}
+
+// fetchLanguages returns the size of the code in the repository for the 100
+// largest languages, along with the total size across all languages.
+func fetchLanguages(ctx context.Context, c *githubapi.Client, owner, name string) (*languageData, error) {
+	s := &struct {
+		Repository struct {
+			Languages languageData `graphql:"languages(first: 100, orderBy: {field: SIZE, direction: DESC})"`
+		} `graphql:"repository(owner: $repositoryOwner, name: $repositoryName)"`
+	}{}
+	vars := map[string]any{
+		"repositoryOwner": graphql.String(owner),
+		"repositoryName":  graphql.String(name),
+	}
+	if err := c.GraphQL().Query(ctx, s, vars); err != nil {
+		return nil, err
+	}
+	return &s.Repository.Languages, nil
+}
+
+// languageStats calculates the language signals for data.
+func languageStats(data *languageData) *languagesSet {
+	bytes := make(map[string]int64)
+	var native int64
+	for _, e := range data.Edges {
+		bytes[e.Node.Name] = e.Size
+		if nativeLanguages[e.Node.Name] {
+			native += e.Size
+		}
+	}
+	s := &languagesSet{
+		TotalBytes:    signal.Val(data.TotalSize),
+		HasNativeCode: signal.Val(native > 0),
+	}
+	s.Bytes.Set(bytes)
+	if data.TotalSize > 0 {
+		s.NativeShare.Set(legacy.Round(float64(native)/float64(data.TotalSize), 2))
+	}
+	return s
+}
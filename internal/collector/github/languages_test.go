@@ -0,0 +1,88 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestLanguageStats(t *testing.T) {
+	//nolint:govet
+	tests := []struct {
+		name       string
+		data       string
+		wantBytes  map[string]int64
+		wantTotal  int64
+		wantNative bool
+		wantShare  float64
+	}{
+		{
+			name: "native",
+			data: `{"totalSize": 1000, "edges": [
+				{"size": 600, "node": {"name": "Go"}},
+				{"size": 300, "node": {"name": "C"}},
+				{"size": 100, "node": {"name": "Unix Assembly"}}
+			]}`,
+			wantBytes:  map[string]int64{"Go": 600, "C": 300, "Unix Assembly": 100},
+			wantTotal:  1000,
+			wantNative: true,
+			wantShare:  0.4,
+		},
+		{
+			name: "no native",
+			data: `{"totalSize": 500, "edges": [
+				{"size": 400, "node": {"name": "Python"}},
+				{"size": 100, "node": {"name": "Shell"}}
+			]}`,
+			wantBytes:  map[string]int64{"Python": 400, "Shell": 100},
+			wantTotal:  500,
+			wantNative: false,
+			wantShare:  0,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var data languageData
+			if err := json.Unmarshal([]byte(test.data), &data); err != nil {
+				t.Fatalf("Unmarshal() = %v, want no error", err)
+			}
+			s := languageStats(&data)
+			if got := s.Bytes.Get(); !reflect.DeepEqual(got, test.wantBytes) {
+				t.Errorf("Bytes = %v, want %v", got, test.wantBytes)
+			}
+			if got := s.TotalBytes.Get(); got != test.wantTotal {
+				t.Errorf("TotalBytes = %d, want %d", got, test.wantTotal)
+			}
+			if got := s.HasNativeCode.Get(); got != test.wantNative {
+				t.Errorf("HasNativeCode = %v, want %v", got, test.wantNative)
+			}
+			if got := s.NativeShare.Get(); got != test.wantShare {
+				t.Errorf("NativeShare = %v, want %v", got, test.wantShare)
+			}
+		})
+	}
+}
+
+func TestLanguageStats_Empty(t *testing.T) {
+	s := languageStats(&languageData{})
+	if got, want := s.TotalBytes.Get(), int64(0); got != want {
+		t.Errorf("TotalBytes = %d, want %d", got, want)
+	}
+	if s.NativeShare.IsSet() {
+		t.Errorf("NativeShare is set, want unset")
+	}
+}
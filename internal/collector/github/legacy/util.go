@@ -16,6 +16,7 @@ package legacy
 
 import (
 	"math"
+	"sort"
 	"time"
 )
 
@@ -37,3 +38,14 @@ func Round(v float64, p int) float64 {
 	m := math.Pow10(p)
 	return math.Round(v*m) / m
 }
+
+// Median returns the median of values, which must not be empty. The values are
+// sorted in place.
+func Median(values []float64) float64 {
+	sort.Float64s(values)
+	n := len(values)
+	if n%2 == 1 {
+		return values[n/2]
+	}
+	return (values[n/2-1] + values[n/2]) / 2
+}
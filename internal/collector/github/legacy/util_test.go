@@ -0,0 +1,37 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package legacy
+
+import "testing"
+
+func TestMedian(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   float64
+	}{
+		{name: "single", values: []float64{3}, want: 3},
+		{name: "odd", values: []float64{5, 1, 3}, want: 3},
+		{name: "even", values: []float64{4, 1, 3, 2}, want: 2.5},
+		{name: "duplicates", values: []float64{2, 2, 7}, want: 2},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Median(test.values); got != test.want {
+				t.Fatalf("Median() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,298 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/hasura/go-graphql-client"
+
+	"github.com/ossf/criticality_score/v2/internal/collector/github/legacy"
+	"github.com/ossf/criticality_score/v2/internal/collector/projectrepo"
+	"github.com/ossf/criticality_score/v2/internal/collector/signal"
+	"github.com/ossf/criticality_score/v2/internal/githubapi"
+	"github.com/ossf/criticality_score/v2/internal/githubapi/pagination"
+)
+
+const (
+	pullsPerPage = 50
+
+	// maxPullRequests limits the number of pull requests fetched for a
+	// repository.
+	maxPullRequests = 5000
+
+	// stalePullPeriod is how long an open pull request must go without being
+	// updated to be considered stale.
+	stalePullPeriod = 90 * 24 * time.Hour
+)
+
+type pullsSet struct {
+	// OpenedCount is the number of pull requests opened during the lookback
+	// period.
+	OpenedCount signal.Field[int] `desc:"Number of pull requests opened." unit:"count" lookback:"90 days" source:"GitHub GraphQL API"`
+
+	// MergedCount is the number of pull requests merged during the lookback
+	// period.
+	MergedCount signal.Field[int] `desc:"Number of pull requests merged." unit:"count" lookback:"90 days" source:"GitHub GraphQL API"`
+
+	// MedianHoursToFirstReview is the median number of hours between a pull
+	// request being opened during the lookback period and its first review by
+	// someone other than the author.
+	MedianHoursToFirstReview signal.Field[float64] `desc:"Median time between a pull request being opened and its first review." unit:"hours" lookback:"90 days" source:"GitHub GraphQL API"`
+
+	// MedianHoursToMerge is the median number of hours between a pull request
+	// being opened and being merged, for pull requests merged during the
+	// lookback period.
+	MedianHoursToMerge signal.Field[float64] `desc:"Median time between a pull request being opened and being merged." unit:"hours" lookback:"90 days" source:"GitHub GraphQL API"`
+
+	// OutsideContributorShare is the fraction of pull requests opened during
+	// the lookback period by authors who are not an owner, member or
+	// collaborator of the repository.
+	OutsideContributorShare signal.Field[float64] `desc:"Fraction of pull requests opened by authors outside the project." unit:"ratio" lookback:"90 days" source:"GitHub GraphQL API"`
+
+	// StaleOpenCount is the number of open pull requests that have not been
+	// updated in the last 90 days.
+	StaleOpenCount signal.Field[int] `desc:"Number of open pull requests not updated in the last 90 days." unit:"count" source:"GitHub GraphQL API"`
+}
+
+func (s *pullsSet) Namespace() signal.Namespace {
+	return signal.Namespace("pulls")
+}
+
+// pullRequest is a single pull request for a repository.
+type pullRequest struct {
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	MergedAt          *time.Time
+	AuthorAssociation string
+	Author            struct {
+		Typename string `graphql:"__typename"`
+		Login    string
+	}
+	Reviews struct {
+		Nodes []struct {
+			CreatedAt time.Time
+			Author    struct{ Login string }
+		}
+	} `graphql:"reviews(first: 10, states: [APPROVED, CHANGES_REQUESTED, COMMENTED, DISMISSED])"`
+}
+
+// isBot returns true if the pull request was opened by a bot account.
+func (pr *pullRequest) isBot() bool {
+	return pr.Author.Typename == "Bot"
+}
+
+// isOutsideContributor returns true if the author of the pull request has no
+// formal association with the repository.
+func (pr *pullRequest) isOutsideContributor() bool {
+	switch pr.AuthorAssociation {
+	case "OWNER", "MEMBER", "COLLABORATOR":
+		return false
+	default:
+		return true
+	}
+}
+
+// firstReview returns the time of the first review by someone other than the
+// author of the pull request.
+func (pr *pullRequest) firstReview() (time.Time, bool) {
+	for _, r := range pr.Reviews.Nodes {
+		if r.Author.Login != pr.Author.Login {
+			return r.CreatedAt, true
+		}
+	}
+	return time.Time{}, false
+}
+
+type pullRequestsQuery struct {
+	Repository struct {
+		PullRequests struct {
+			Nodes    []pullRequest
+			PageInfo struct {
+				EndCursor   string
+				HasNextPage bool
+			}
+			TotalCount int
+		} `graphql:"pullRequests(first: $perPage, after: $endCursor, orderBy: {field: UPDATED_AT, direction: DESC})"`
+	} `graphql:"repository(owner: $repositoryOwner, name: $repositoryName)"`
+}
+
+// Reset implements the pagination.PagedQuery interface.
+func (q *pullRequestsQuery) Reset() {
+	q.Repository.PullRequests.Nodes = nil
+}
+
+// Total implements the pagination.PagedQuery interface.
+func (q *pullRequestsQuery) Total() int {
+	return q.Repository.PullRequests.TotalCount
+}
+
+// Length implements the pagination.PagedQuery interface.
+func (q *pullRequestsQuery) Length() int {
+	return len(q.Repository.PullRequests.Nodes)
+}
+
+// Get implements the pagination.PagedQuery interface.
+func (q *pullRequestsQuery) Get(i int) any {
+	return q.Repository.PullRequests.Nodes[i]
+}
+
+// HasNextPage implements the pagination.PagedQuery interface.
+func (q *pullRequestsQuery) HasNextPage() bool {
+	return q.Repository.PullRequests.PageInfo.HasNextPage
+}
+
+// NextPageVars implements the pagination.PagedQuery interface.
+func (q *pullRequestsQuery) NextPageVars() map[string]any {
+	cursor := q.Repository.PullRequests.PageInfo.EndCursor
+	if cursor == "" {
+		return map[string]any{
+			"endCursor": (*graphql.String)(nil),
+		}
+	}
+	return map[string]any{
+		"endCursor": graphql.String(cursor),
+	}
+}
+
+// fetchPullRequests returns the pull requests for the repository that have
+// been updated since the given time, most recently updated first.
+//
+// At most maxPullRequests pull requests are returned.
+func fetchPullRequests(ctx context.Context, c *githubapi.Client, owner, name string, since time.Time) ([]pullRequest, error) {
+	vars := map[string]any{
+		"perPage":         graphql.Int(pullsPerPage),
+		"repositoryOwner": graphql.String(owner),
+		"repositoryName":  graphql.String(name),
+	}
+	cursor, err := pagination.Query(ctx, c.GraphQL(), &pullRequestsQuery{}, vars)
+	if err != nil {
+		return nil, err
+	}
+	var prs []pullRequest
+	for len(prs) < maxPullRequests {
+		obj, err := cursor.Next()
+		if obj == nil && errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		pr := obj.(pullRequest)
+		if pr.UpdatedAt.Before(since) {
+			break
+		}
+		prs = append(prs, pr)
+	}
+	return prs, nil
+}
+
+// fetchStalePullCount returns the number of open pull requests for the
+// repository that have not been updated since the given time.
+func fetchStalePullCount(ctx context.Context, c *githubapi.Client, owner, name string, since time.Time) (int, error) {
+	s := &struct {
+		Search struct {
+			IssueCount int
+		} `graphql:"search(query: $query, type: ISSUE)"`
+	}{}
+	vars := map[string]any{
+		"query": graphql.String(fmt.Sprintf("repo:%s/%s is:pr is:open updated:<%s", owner, name, since.Format(time.DateOnly))),
+	}
+	if err := c.GraphQL().Query(ctx, s, vars); err != nil {
+		return 0, err
+	}
+	return s.Search.IssueCount, nil
+}
+
+// PullsSource collects signals about how pull requests to a GitHub repository
+// are handled. Pull requests opened by bots are ignored.
+type PullsSource struct{}
+
+func (ps *PullsSource) EmptySet() signal.Set {
+	return &pullsSet{}
+}
+
+func (ps *PullsSource) IsSupported(r projectrepo.Repo) bool {
+	_, ok := r.(*repo)
+	return ok
+}
+
+func (ps *PullsSource) Get(ctx context.Context, r projectrepo.Repo, _ string) (signal.Set, error) {
+	ghr, ok := r.(*repo)
+	if !ok {
+		return nil, errors.New("project is not a github project")
+	}
+	now := time.Now().UTC()
+
+	ghr.logger.Debug("Fetching pull requests")
+	prs, err := fetchPullRequests(ctx, ghr.client, ghr.owner(), ghr.name(), now.Add(-legacy.IssueLookback))
+	if err != nil {
+		return nil, fmt.Errorf("fetch pull requests: %w", err)
+	}
+	s := pullStats(prs, now.Add(-legacy.IssueLookback))
+
+	ghr.logger.Debug("Fetching stale pull request count")
+	stale, err := fetchStalePullCount(ctx, ghr.client, ghr.owner(), ghr.name(), now.Add(-stalePullPeriod))
+	if err != nil {
+		return nil, fmt.Errorf("fetch stale pull requests: %w", err)
+	}
+	s.StaleOpenCount.Set(stale)
+	return s, nil
+}
+
+// pullStats calculates the pull request signals for the pull requests that
+// were opened or merged since the given time.
+func pullStats(prs []pullRequest, since time.Time) *pullsSet {
+	opened := 0
+	merged := 0
+	outside := 0
+	var hoursToReview, hoursToMerge []float64
+	for i := range prs {
+		pr := &prs[i]
+		if pr.isBot() {
+			continue
+		}
+		if pr.CreatedAt.After(since) {
+			opened++
+			if pr.isOutsideContributor() {
+				outside++
+			}
+			if t, ok := pr.firstReview(); ok {
+				hoursToReview = append(hoursToReview, t.Sub(pr.CreatedAt).Hours())
+			}
+		}
+		if pr.MergedAt != nil && pr.MergedAt.After(since) {
+			merged++
+			hoursToMerge = append(hoursToMerge, pr.MergedAt.Sub(pr.CreatedAt).Hours())
+		}
+	}
+
+	s := &pullsSet{}
+	s.OpenedCount.Set(opened)
+	s.MergedCount.Set(merged)
+	if opened > 0 {
+		s.OutsideContributorShare.Set(legacy.Round(float64(outside)/float64(opened), 2))
+	}
+	if len(hoursToReview) > 0 {
+		s.MedianHoursToFirstReview.Set(legacy.Round(legacy.Median(hoursToReview), 2))
+	}
+	if len(hoursToMerge) > 0 {
+		s.MedianHoursToMerge.Set(legacy.Round(legacy.Median(hoursToMerge), 2))
+	}
+	return s
+}
@@ -0,0 +1,170 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ossf/criticality_score/v2/internal/collector/github/legacy"
+	"github.com/ossf/criticality_score/v2/internal/collector/signal"
+)
+
+func TestPullStats(t *testing.T) {
+	since := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	//nolint:govet
+	tests := []struct {
+		name string
+		prs  string
+		want *pullsSet
+	}{
+		{
+			name: "pull requests",
+			prs: `[
+				{"createdAt": "2023-06-20T00:00:00Z", "mergedAt": "2023-06-20T01:00:00Z", "authorAssociation": "NONE", "author": {"__typename": "Bot", "login": "dependabot"}, "reviews": {"nodes": []}},
+				{"createdAt": "2023-06-12T00:00:00Z", "mergedAt": null, "authorAssociation": "NONE", "author": {"__typename": "User", "login": "dave"}, "reviews": {"nodes": []}},
+				{"createdAt": "2023-06-11T00:00:00Z", "mergedAt": null, "authorAssociation": "CONTRIBUTOR", "author": {"__typename": "User", "login": "carol"}, "reviews": {"nodes": [
+					{"createdAt": "2023-06-11T01:00:00Z", "author": {"login": "carol"}},
+					{"createdAt": "2023-06-12T06:00:00Z", "author": {"login": "alice"}}
+				]}},
+				{"createdAt": "2023-06-10T00:00:00Z", "mergedAt": "2023-06-10T20:00:00Z", "authorAssociation": "MEMBER", "author": {"__typename": "User", "login": "alice"}, "reviews": {"nodes": [
+					{"createdAt": "2023-06-10T10:00:00Z", "author": {"login": "bob"}}
+				]}},
+				{"createdAt": "2023-05-01T00:00:00Z", "mergedAt": "2023-06-01T12:00:00Z", "authorAssociation": "COLLABORATOR", "author": {"__typename": "User", "login": "erin"}, "reviews": {"nodes": []}}
+			]`,
+			want: &pullsSet{
+				OpenedCount:              signal.Val(3),
+				MergedCount:              signal.Val(2),
+				MedianHoursToFirstReview: signal.Val(20.0),
+				MedianHoursToMerge:       signal.Val(388.0),
+				OutsideContributorShare:  signal.Val(0.67),
+			},
+		},
+		{
+			name: "only bots",
+			prs: `[
+				{"createdAt": "2023-06-20T00:00:00Z", "mergedAt": "2023-06-20T01:00:00Z", "authorAssociation": "NONE", "author": {"__typename": "Bot", "login": "dependabot"}, "reviews": {"nodes": []}}
+			]`,
+			want: &pullsSet{
+				OpenedCount: signal.Val(0),
+				MergedCount: signal.Val(0),
+			},
+		},
+		{
+			name: "no pull requests",
+			prs:  `[]`,
+			want: &pullsSet{
+				OpenedCount: signal.Val(0),
+				MergedCount: signal.Val(0),
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			prs := decodeNodes[pullRequest](t, test.prs)
+			got := pullStats(prs, since)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("pullStats() = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestPullsSource_Get(t *testing.T) {
+	now := time.Now().UTC()
+	pullsPage := func(updated time.Time, hasNextPage bool) string {
+		node := fmt.Sprintf(`{"createdAt": %q, "updatedAt": %q, "authorAssociation": "MEMBER", "author": {"__typename": "User", "login": "alice"}, "reviews": {"nodes": []}}`,
+			updated.Format(time.RFC3339), updated.Format(time.RFC3339))
+		return fmt.Sprintf(`{"repository": {"pullRequests": {
+			"nodes": [%s],
+			"pageInfo": {"endCursor": "next", "hasNextPage": %t},
+			"totalCount": 100000
+		}}}`, strings.Repeat(node+",", pullsPerPage-1)+node, hasNextPage)
+	}
+	//nolint:govet
+	tests := []struct {
+		name         string
+		page         string
+		wantRequests int
+		wantOpened   int
+	}{
+		{
+			name:         "capped",
+			page:         pullsPage(now.Add(-time.Hour), true),
+			wantRequests: maxPullRequests / pullsPerPage,
+			wantOpened:   maxPullRequests,
+		},
+		{
+			name:         "last page",
+			page:         pullsPage(now.Add(-time.Hour), false),
+			wantRequests: 1,
+			wantOpened:   pullsPerPage,
+		},
+		{
+			name:         "updated before lookback",
+			page:         pullsPage(now.Add(-legacy.IssueLookback-time.Hour), true),
+			wantRequests: 1,
+			wantOpened:   0,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f := newFakeGraphQL(t, func(req graphQLRequest) string {
+				if strings.Contains(req.Query, "search(") {
+					return `{"search": {"issueCount": 7}}`
+				}
+				return test.page
+			})
+
+			s, err := (&PullsSource{}).Get(context.Background(), f.repo(t), "")
+			if err != nil {
+				t.Fatalf("Get() = %v, want no error", err)
+			}
+
+			reqs := f.queries("pullRequests(first: $perPage, after: $endCursor, orderBy: {field: UPDATED_AT, direction: DESC})")
+			if got := len(reqs); got != test.wantRequests {
+				t.Fatalf("pull request requests = %d, want %d", got, test.wantRequests)
+			}
+			vars := reqs[0].Variables
+			if vars["repositoryOwner"] != "owner" || vars["repositoryName"] != "repo" {
+				t.Errorf("repository = %v/%v, want owner/repo", vars["repositoryOwner"], vars["repositoryName"])
+			}
+			if got, want := vars["perPage"], float64(pullsPerPage); got != want {
+				t.Errorf("perPage = %v, want %v", got, want)
+			}
+
+			searches := f.queries("search(query: $query, type: ISSUE)")
+			if got, want := len(searches), 1; got != want {
+				t.Fatalf("search requests = %d, want %d", got, want)
+			}
+			wantQuery := fmt.Sprintf("repo:owner/repo is:pr is:open updated:<%s", now.Add(-stalePullPeriod).Format(time.DateOnly))
+			if got := searches[0].Variables["query"]; got != wantQuery {
+				t.Errorf("search query = %v, want %v", got, wantQuery)
+			}
+
+			ps := s.(*pullsSet)
+			if got := ps.OpenedCount.Get(); got != test.wantOpened {
+				t.Errorf("OpenedCount = %d, want %d", got, test.wantOpened)
+			}
+			if got, want := ps.StaleOpenCount.Get(), 7; got != want {
+				t.Errorf("StaleOpenCount = %d, want %d", got, want)
+			}
+		})
+	}
+}
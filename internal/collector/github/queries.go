@@ -47,6 +47,7 @@ type basicRepoData struct {
 		Target struct {
 			Commit struct { // this is the last commit
 				AuthoredDate  time.Time
+				CommittedDate time.Time
 				RecentCommits struct {
 					TotalCount int
 				} `graphql:"recentcommits:history(since:$legacyCommitLookback)"`
@@ -60,8 +61,13 @@ type basicRepoData struct {
 	IsDisabled       bool
 	IsEmpty          bool
 	IsMirror         bool
+	IsFork           bool
+	ForkCount        int
 
-	Watchers struct{ TotalCount int }
+	Parent *struct{ URL string }
+
+	Watchers   struct{ TotalCount int }
+	OpenIssues struct{ TotalCount int } `graphql:"openissues:issues(states: OPEN)"`
 
 	Tags struct {
 		TotalCount int
@@ -0,0 +1,256 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hasura/go-graphql-client"
+	"golang.org/x/mod/semver"
+
+	"github.com/ossf/criticality_score/v2/internal/collector/github/legacy"
+	"github.com/ossf/criticality_score/v2/internal/collector/projectrepo"
+	"github.com/ossf/criticality_score/v2/internal/collector/signal"
+	"github.com/ossf/criticality_score/v2/internal/githubapi"
+	"github.com/ossf/criticality_score/v2/internal/githubapi/pagination"
+)
+
+const (
+	// maxReleases limits the number of recent releases used for release
+	// signals.
+	maxReleases = 100
+)
+
+// signatureSuffixes are the suffixes of release asset names that contain a
+// signature or attestation for the other assets.
+var signatureSuffixes = []string{
+	".asc",
+	".intoto.jsonl",
+	".sig",
+	".sigstore",
+	".sigstore.json",
+}
+
+type releasesSet struct {
+	// LastReleaseDate is when the most recent release was published.
+	LastReleaseDate signal.Field[time.Time] `desc:"When the most recent release was published." lookback:"100 releases" source:"GitHub GraphQL API"`
+
+	// MedianDaysBetween is the median number of days between consecutive
+	// releases.
+	MedianDaysBetween signal.Field[float64] `desc:"Median time between consecutive releases." unit:"days" lookback:"100 releases" source:"GitHub GraphQL API"`
+
+	// UsesSemver is true if the tag of every release is a semantic version,
+	// with an optional "v" prefix.
+	UsesSemver signal.Field[bool] `desc:"Whether every release is tagged with a semantic version." lookback:"100 releases" source:"GitHub GraphQL API"`
+
+	// PrereleaseRatio is the fraction of releases marked as a prerelease.
+	PrereleaseRatio signal.Field[float64] `desc:"Fraction of releases marked as a prerelease." unit:"ratio" lookback:"100 releases" source:"GitHub GraphQL API"`
+
+	// HasSignedAssets is true if any release includes an asset containing a
+	// signature or attestation, such as a ".sig" or ".intoto.jsonl" file.
+	HasSignedAssets signal.Field[bool] `desc:"Whether any release includes a signature or attestation." lookback:"100 releases" source:"GitHub GraphQL API"`
+}
+
+func (s *releasesSet) Namespace() signal.Namespace {
+	return signal.Namespace("releases")
+}
+
+// release is a single published release for a repository.
+type release struct {
+	TagName       string
+	PublishedAt   *time.Time
+	IsDraft       bool
+	IsPrerelease  bool
+	ReleaseAssets struct {
+		Nodes []struct{ Name string }
+	} `graphql:"releaseAssets(first: 50)"`
+}
+
+// hasSignature returns true if any of the release's assets is a signature or
+// attestation.
+func (r *release) hasSignature() bool {
+	for _, a := range r.ReleaseAssets.Nodes {
+		for _, suffix := range signatureSuffixes {
+			if strings.HasSuffix(strings.ToLower(a.Name), suffix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isSemver returns true if tag is a semantic version. The tag may be
+// prefixed with a "v".
+func isSemver(tag string) bool {
+	v := tag
+	if !strings.HasPrefix(v, "v") {
+		v = "v" + v
+	}
+	// semver.Canonical drops any build metadata.
+	v, _, _ = strings.Cut(v, "+")
+	// semver.IsValid accepts shorthands like "v1.2", which Canonical expands.
+	return semver.IsValid(v) && semver.Canonical(v) == v
+}
+
+type releasesQuery struct {
+	Repository struct {
+		Releases struct {
+			Nodes    []release
+			PageInfo struct {
+				EndCursor   string
+				HasNextPage bool
+			}
+			TotalCount int
+		} `graphql:"releases(first: $perPage, after: $endCursor, orderBy: {field: CREATED_AT, direction: DESC})"`
+	} `graphql:"repository(owner: $repositoryOwner, name: $repositoryName)"`
+}
+
+// Reset implements the pagination.PagedQuery interface.
+func (q *releasesQuery) Reset() {
+	q.Repository.Releases.Nodes = nil
+}
+
+// Total implements the pagination.PagedQuery interface.
+func (q *releasesQuery) Total() int {
+	return q.Repository.Releases.TotalCount
+}
+
+// Length implements the pagination.PagedQuery interface.
+func (q *releasesQuery) Length() int {
+	return len(q.Repository.Releases.Nodes)
+}
+
+// Get implements the pagination.PagedQuery interface.
+func (q *releasesQuery) Get(i int) any {
+	return q.Repository.Releases.Nodes[i]
+}
+
+// HasNextPage implements the pagination.PagedQuery interface.
+func (q *releasesQuery) HasNextPage() bool {
+	return q.Repository.Releases.PageInfo.HasNextPage
+}
+
+// NextPageVars implements the pagination.PagedQuery interface.
+func (q *releasesQuery) NextPageVars() map[string]any {
+	cursor := q.Repository.Releases.PageInfo.EndCursor
+	if cursor == "" {
+		return map[string]any{
+			"endCursor": (*graphql.String)(nil),
+		}
+	}
+	return map[string]any{
+		"endCursor": graphql.String(cursor),
+	}
+}
+
+// fetchReleases returns up to maxReleases of the most recently created
+// releases for the repository that have been published.
+func fetchReleases(ctx context.Context, c *githubapi.Client, owner, name string) ([]release, error) {
+	vars := map[string]any{
+		"perPage":         graphql.Int(maxReleases),
+		"repositoryOwner": graphql.String(owner),
+		"repositoryName":  graphql.String(name),
+	}
+	cursor, err := pagination.Query(ctx, c.GraphQL(), &releasesQuery{}, vars)
+	if err != nil {
+		return nil, err
+	}
+	var releases []release
+	for len(releases) < maxReleases {
+		obj, err := cursor.Next()
+		if obj == nil && errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		r := obj.(release)
+		if r.IsDraft || r.PublishedAt == nil {
+			continue
+		}
+		releases = append(releases, r)
+	}
+	return releases, nil
+}
+
+// ReleasesSource collects signals about the releases published for a GitHub
+// repository.
+type ReleasesSource struct{}
+
+func (rs *ReleasesSource) EmptySet() signal.Set {
+	return &releasesSet{}
+}
+
+func (rs *ReleasesSource) IsSupported(r projectrepo.Repo) bool {
+	_, ok := r.(*repo)
+	return ok
+}
+
+func (rs *ReleasesSource) Get(ctx context.Context, r projectrepo.Repo, _ string) (signal.Set, error) {
+	ghr, ok := r.(*repo)
+	if !ok {
+		return nil, errors.New("project is not a github project")
+	}
+	ghr.logger.Debug("Fetching releases")
+	releases, err := fetchReleases(ctx, ghr.client, ghr.owner(), ghr.name())
+	if err != nil {
+		return nil, fmt.Errorf("fetch releases: %w", err)
+	}
+	return releaseStats(releases), nil
+}
+
+// releaseStats calculates the release signals for the published releases.
+func releaseStats(releases []release) *releasesSet {
+	s := &releasesSet{}
+	if len(releases) == 0 {
+		return s
+	}
+	published := make([]time.Time, 0, len(releases))
+	semverTags := true
+	prereleases := 0
+	signed := false
+	for i := range releases {
+		r := &releases[i]
+		published = append(published, *r.PublishedAt)
+		if !isSemver(r.TagName) {
+			semverTags = false
+		}
+		if r.IsPrerelease {
+			prereleases++
+		}
+		if r.hasSignature() {
+			signed = true
+		}
+	}
+	sort.Slice(published, func(i, j int) bool { return published[i].Before(published[j]) })
+
+	s.LastReleaseDate.Set(published[len(published)-1])
+	s.UsesSemver.Set(semverTags)
+	s.PrereleaseRatio.Set(legacy.Round(float64(prereleases)/float64(len(releases)), 2))
+	s.HasSignedAssets.Set(signed)
+	if len(published) > 1 {
+		days := make([]float64, 0, len(published)-1)
+		for i := 1; i < len(published); i++ {
+			days = append(days, published[i].Sub(published[i-1]).Hours()/24)
+		}
+		s.MedianDaysBetween.Set(legacy.Round(legacy.Median(days), 2))
+	}
+	return s
+}
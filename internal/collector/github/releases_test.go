@@ -0,0 +1,171 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ossf/criticality_score/v2/internal/collector/signal"
+)
+
+func TestIsSemver(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want bool
+	}{
+		{tag: "v1.2.3", want: true},
+		{tag: "1.2.3", want: true},
+		{tag: "v1.2.3-rc.1", want: true},
+		{tag: "v1.2.3+build.5", want: true},
+		{tag: "v1.2", want: false},
+		{tag: "release-1.2.3", want: false},
+		{tag: "2023.06.01", want: false},
+	}
+	for _, test := range tests {
+		t.Run(test.tag, func(t *testing.T) {
+			if got := isSemver(test.tag); got != test.want {
+				t.Fatalf("isSemver(%q) = %v, want %v", test.tag, got, test.want)
+			}
+		})
+	}
+}
+
+func TestReleaseStats(t *testing.T) {
+	//nolint:govet
+	tests := []struct {
+		name     string
+		releases string
+		want     *releasesSet
+	}{
+		{
+			name: "releases",
+			releases: `[
+				{"tagName": "v1.2.0", "publishedAt": "2023-06-25T00:00:00Z", "isPrerelease": false, "releaseAssets": {"nodes": [{"name": "tool.tar.gz"}, {"name": "tool.tar.gz.sig"}]}},
+				{"tagName": "v1.2.0-rc.1", "publishedAt": "2023-06-15T00:00:00Z", "isPrerelease": true, "releaseAssets": {"nodes": [{"name": "tool.tar.gz"}]}},
+				{"tagName": "v1.1.0", "publishedAt": "2023-05-16T00:00:00Z", "isPrerelease": false, "releaseAssets": {"nodes": []}},
+				{"tagName": "v1.0.0", "publishedAt": "2023-03-17T00:00:00Z", "isPrerelease": false, "releaseAssets": {"nodes": []}}
+			]`,
+			want: &releasesSet{
+				LastReleaseDate:   signal.Val(time.Date(2023, 6, 25, 0, 0, 0, 0, time.UTC)),
+				MedianDaysBetween: signal.Val(30.0),
+				UsesSemver:        signal.Val(true),
+				PrereleaseRatio:   signal.Val(0.25),
+				HasSignedAssets:   signal.Val(true),
+			},
+		},
+		{
+			name: "single release without semver",
+			releases: `[
+				{"tagName": "nightly", "publishedAt": "2023-06-01T00:00:00Z", "isPrerelease": true, "releaseAssets": {"nodes": [{"name": "tool.tar.gz"}]}}
+			]`,
+			want: &releasesSet{
+				LastReleaseDate: signal.Val(time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)),
+				UsesSemver:      signal.Val(false),
+				PrereleaseRatio: signal.Val(1.0),
+				HasSignedAssets: signal.Val(false),
+			},
+		},
+		{
+			name:     "no releases",
+			releases: `[]`,
+			want:     &releasesSet{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			releases := decodeNodes[release](t, test.releases)
+			got := releaseStats(releases)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("releaseStats() = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestReleasesSource_Get(t *testing.T) {
+	published := `{"tagName": "v1.0.0", "publishedAt": "2023-06-01T00:00:00Z", "isDraft": false, "isPrerelease": false, "releaseAssets": {"nodes": []}}`
+	prerelease := `{"tagName": "v1.0.0-rc.1", "publishedAt": "2023-05-01T00:00:00Z", "isDraft": false, "isPrerelease": true, "releaseAssets": {"nodes": []}}`
+	draft := `{"tagName": "v2.0.0", "publishedAt": null, "isDraft": true, "isPrerelease": false, "releaseAssets": {"nodes": []}}`
+	releasesPage := func(nodes []string, hasNextPage bool) string {
+		return fmt.Sprintf(`{"repository": {"releases": {
+			"nodes": [%s],
+			"pageInfo": {"endCursor": "next", "hasNextPage": %t},
+			"totalCount": 1000
+		}}}`, strings.Join(nodes, ","), hasNextPage)
+	}
+	//nolint:govet
+	tests := []struct {
+		name           string
+		pages          []string
+		wantRequests   int
+		wantPrerelease float64
+	}{
+		{
+			name: "capped",
+			pages: []string{
+				releasesPage(append(slices.Repeat([]string{published}, maxReleases-1), prerelease), true),
+			},
+			wantRequests:   1,
+			wantPrerelease: 0.01,
+		},
+		{
+			name: "drafts skipped",
+			pages: []string{
+				releasesPage([]string{draft, published}, true),
+				releasesPage([]string{draft, prerelease}, false),
+			},
+			wantRequests:   2,
+			wantPrerelease: 0.5,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			page := 0
+			f := newFakeGraphQL(t, func(graphQLRequest) string {
+				p := test.pages[min(page, len(test.pages)-1)]
+				page++
+				return p
+			})
+
+			s, err := (&ReleasesSource{}).Get(context.Background(), f.repo(t), "")
+			if err != nil {
+				t.Fatalf("Get() = %v, want no error", err)
+			}
+
+			reqs := f.queries("releases(first: $perPage, after: $endCursor, orderBy: {field: CREATED_AT, direction: DESC})")
+			if got := len(reqs); got != test.wantRequests {
+				t.Fatalf("release requests = %d, want %d", got, test.wantRequests)
+			}
+			vars := reqs[0].Variables
+			if vars["repositoryOwner"] != "owner" || vars["repositoryName"] != "repo" {
+				t.Errorf("repository = %v/%v, want owner/repo", vars["repositoryOwner"], vars["repositoryName"])
+			}
+			if got, want := vars["perPage"], float64(maxReleases); got != want {
+				t.Errorf("perPage = %v, want %v", got, want)
+			}
+
+			rs := s.(*releasesSet)
+			if got := rs.PrereleaseRatio.Get(); got != test.wantPrerelease {
+				t.Errorf("PrereleaseRatio = %v, want %v", got, test.wantPrerelease)
+			}
+		})
+	}
+}
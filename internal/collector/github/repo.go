@@ -66,6 +66,18 @@ func (r *repo) init(ctx context.Context) error {
 	return nil
 }
 
+// ParentURL implements the projectrepo.Fork interface.
+func (r *repo) ParentURL() *url.URL {
+	if r.BasicData.Parent == nil {
+		return nil
+	}
+	u, err := url.Parse(r.BasicData.Parent.URL)
+	if err != nil {
+		return nil
+	}
+	return u
+}
+
 func (r *repo) owner() string {
 	return r.BasicData.Owner.Login
 }
@@ -78,6 +90,13 @@ func (r *repo) updatedAt() time.Time {
 	return r.BasicData.DefaultBranchRef.Target.Commit.AuthoredDate
 }
 
+// lastCommittedAt returns the commit date of the last commit to the default
+// branch. Unlike updatedAt, this is the date the commit history is ordered and
+// filtered by.
+func (r *repo) lastCommittedAt() time.Time {
+	return r.BasicData.DefaultBranchRef.Target.Commit.CommittedDate
+}
+
 func (r *repo) createdAt() time.Time {
 	return r.created
 }
@@ -46,9 +46,25 @@ func (rc *RepoSource) Get(ctx context.Context, r projectrepo.Repo, _ string) (si
 		CreatedSince: signal.Val(legacy.TimeDelta(now, ghr.createdAt(), legacy.SinceDuration)),
 		UpdatedAt:    signal.Val(ghr.updatedAt()),
 		UpdatedSince: signal.Val(legacy.TimeDelta(now, ghr.updatedAt(), legacy.SinceDuration)),
+
+		WatcherCount:     signal.Val(ghr.BasicData.Watchers.TotalCount),
+		ForkCount:        signal.Val(ghr.BasicData.ForkCount),
+		IsArchived:       signal.Val(ghr.BasicData.IsArchived),
+		IsMirror:         signal.Val(ghr.BasicData.IsMirror),
+		IsDisabled:       signal.Val(ghr.BasicData.IsDisabled),
+		IsEmpty:          signal.Val(ghr.BasicData.IsEmpty),
+		HasIssuesEnabled: signal.Val(ghr.BasicData.HasIssuesEnabled),
+		IsFork:           signal.Val(ghr.BasicData.IsFork),
+
 		// Note: the /stats/commit-activity REST endpoint used in the legacy Python codebase is stale.
 		CommitFrequency: signal.Val(legacy.Round(float64(ghr.BasicData.DefaultBranchRef.Target.Commit.RecentCommits.TotalCount)/52, 2)),
 	}
+	if ghr.BasicData.IsMirror {
+		s.MirrorURL.Set(ghr.BasicData.MirrorURL)
+	}
+	if ghr.BasicData.Parent != nil {
+		s.ParentURL.Set(ghr.BasicData.Parent.URL)
+	}
 	ghr.logger.Debug("Fetching contributors")
 	if contributors, err := legacy.FetchTotalContributors(ctx, ghr.client, ghr.owner(), ghr.name()); err != nil {
 		return nil, err
@@ -85,7 +101,20 @@ func (rc *RepoSource) IsSupported(p projectrepo.Repo) bool {
 	return ok
 }
 
-type IssuesSource struct{}
+// IssuesSource collects the signals about the issues for a GitHub repository.
+//
+// The issue triage signals require paging through every issue updated during
+// the lookback period, which is much more expensive for busy repositories
+// than the legacy counts, so they are only collected if enabled.
+type IssuesSource struct {
+	triage bool
+}
+
+// NewIssuesSource creates a new IssuesSource. If triage is true, the issue
+// triage signals are collected along with the legacy issue signals.
+func NewIssuesSource(triage bool) signal.Source {
+	return &IssuesSource{triage: triage}
+}
 
 func (ic *IssuesSource) EmptySet() signal.Set {
 	return &signal.IssuesSet{}
@@ -98,6 +127,12 @@ func (ic *IssuesSource) Get(ctx context.Context, r projectrepo.Repo, _ string) (
 	}
 	s := &signal.IssuesSet{}
 
+	if ic.triage {
+		if err := setIssueTriage(ctx, ghr, s); err != nil {
+			return nil, err
+		}
+	}
+
 	ghr.logger.Debug("Fetching closed issues")
 	closed, err := legacy.FetchIssueCount(ctx, ghr.client, ghr.owner(), ghr.name(), legacy.IssueStateClosed, legacy.IssueLookback)
 	if err != nil {
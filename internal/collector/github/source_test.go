@@ -0,0 +1,133 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hasura/go-graphql-client/pkg/jsonutil"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/ossf/criticality_score/v2/internal/githubapi"
+)
+
+// graphQLRequest is a request received by fakeGraphQL.
+type graphQLRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables"`
+}
+
+// fakeGraphQL is a minimal stand-in for the GitHub GraphQL API. The data for
+// each response is returned by respond, and every request is recorded.
+//
+// Requests for the REST API are answered with the body in rest for the path,
+// if there is one.
+type fakeGraphQL struct {
+	*httptest.Server
+	respond func(req graphQLRequest) string
+	rest    map[string]string
+
+	mu       sync.Mutex
+	requests []graphQLRequest
+}
+
+func newFakeGraphQL(t *testing.T, respond func(req graphQLRequest) string) *fakeGraphQL {
+	t.Helper()
+	f := &fakeGraphQL{respond: respond}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.serve))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeGraphQL) serve(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/graphql" {
+		body, ok := f.rest[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+		return
+	}
+	var req graphQLRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	f.mu.Lock()
+	f.requests = append(f.requests, req)
+	f.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(`{"data":` + f.respond(req) + `}`))
+}
+
+// queries returns the requests received whose query contains s.
+func (f *fakeGraphQL) queries(s string) []graphQLRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var reqs []graphQLRequest
+	for _, req := range f.requests {
+		if strings.Contains(req.Query, s) {
+			reqs = append(reqs, req)
+		}
+	}
+	return reqs
+}
+
+// repo returns a repo for "owner/repo" that sends its requests to f.
+func (f *fakeGraphQL) repo(t *testing.T) *repo {
+	t.Helper()
+	u, err := url.Parse(f.URL)
+	if err != nil {
+		t.Fatalf("Parse() = %v, want no error", err)
+	}
+	client := &http.Client{Transport: &redirectTransport{target: u}}
+	r := &repo{
+		client:    githubapi.NewClient(client),
+		logger:    zaptest.NewLogger(t),
+		BasicData: &basicRepoData{Name: "repo"},
+	}
+	r.BasicData.Owner.Login = "owner"
+	return r
+}
+
+// redirectTransport sends every request to the host of target.
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (rt *redirectTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.URL.Scheme = rt.target.Scheme
+	r.URL.Host = rt.target.Host
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+// decodeNodes decodes a JSON array of GraphQL nodes of type T.
+func decodeNodes[T any](t *testing.T, data string) []T {
+	t.Helper()
+	var v struct{ Nodes []T }
+	if err := jsonutil.UnmarshalGraphQL([]byte(`{"nodes":`+data+`}`), &v); err != nil {
+		t.Fatalf("UnmarshalGraphQL() = %v, want no error", err)
+	}
+	return v.Nodes
+}
@@ -0,0 +1,161 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package githubdependents provides a signal Source for the number of
+// repositories and packages that depend on a GitHub repository, as shown by
+// GitHub's dependency graph.
+//
+// The dependency graph counts are not available from the GitHub APIs, so they
+// are scraped from the repository's "Used by" page. The page is slow to render
+// for popular repositories and may fail with a server error, so requests
+// should be retried.
+package githubdependents
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/ossf/criticality_score/v2/internal/collector/projectrepo"
+	"github.com/ossf/criticality_score/v2/internal/collector/signal"
+)
+
+// DefaultBaseURL is the URL of the GitHub website.
+const DefaultBaseURL = "https://github.com"
+
+// maxPageSize limits the amount of the dependents page that is read.
+const maxPageSize = 10 << 20
+
+var (
+	repositoriesRe = regexp.MustCompile(`(?s)dependent_type=REPOSITORY".*?([\d,]+)\s+Repositor`)
+	packagesRe     = regexp.MustCompile(`(?s)dependent_type=PACKAGE".*?([\d,]+)\s+Package`)
+)
+
+type dependentsSet struct {
+	// RepositoryCount is the number of repositories that depend on the
+	// repository.
+	RepositoryCount signal.Field[int] `desc:"Number of repositories that depend on the repository." unit:"count" source:"GitHub website"`
+
+	// PackageCount is the number of packages that depend on the repository.
+	PackageCount signal.Field[int] `desc:"Number of packages that depend on the repository." unit:"count" source:"GitHub website"`
+}
+
+func (s *dependentsSet) Namespace() signal.Namespace {
+	return signal.Namespace("github_dependents")
+}
+
+type Source struct {
+	logger  *zap.Logger
+	client  *http.Client
+	baseURL string
+}
+
+// NewSource creates a new Source that uses client to fetch the dependents
+// page for repositories from the GitHub website at baseURL.
+func NewSource(logger *zap.Logger, client *http.Client, baseURL string) signal.Source {
+	return &Source{
+		logger:  logger,
+		client:  client,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+func (c *Source) EmptySet() signal.Set {
+	return &dependentsSet{}
+}
+
+func (c *Source) IsSupported(r projectrepo.Repo) bool {
+	_, ok := repoPath(r.URL())
+	return ok
+}
+
+func (c *Source) Get(ctx context.Context, r projectrepo.Repo, _ string) (signal.Set, error) {
+	s := &dependentsSet{}
+	path, ok := repoPath(r.URL())
+	if !ok {
+		return s, nil
+	}
+	c.logger.With(zap.String("url", r.URL().String())).Debug("Fetching dependents page")
+	page, err := c.fetchPage(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("fetch dependents: %w", err)
+	}
+	if page == nil {
+		// The repository does not have a dependency graph.
+		return s, nil
+	}
+	if n, ok := parseCount(repositoriesRe, page); ok {
+		s.RepositoryCount.Set(n)
+	}
+	if n, ok := parseCount(packagesRe, page); ok {
+		s.PackageCount.Set(n)
+	}
+	return s, nil
+}
+
+// fetchPage returns the HTML of the dependents page for the repository, or nil
+// if the page does not exist.
+func (c *Source) fetchPage(ctx context.Context, path string) ([]byte, error) {
+	u := c.baseURL + "/" + path + "/network/dependents"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxPageSize))
+}
+
+// repoPath returns the "owner/name" path of a GitHub repository URL.
+func repoPath(u *url.URL) (string, bool) {
+	if !strings.EqualFold(u.Hostname(), "github.com") {
+		return "", false
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", false
+	}
+	return parts[0] + "/" + strings.TrimSuffix(parts[1], ".git"), true
+}
+
+// parseCount returns the count captured by re in the page, ignoring the
+// thousands separators.
+func parseCount(re *regexp.Regexp, page []byte) (int, bool) {
+	m := re.FindSubmatch(page)
+	if m == nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(strings.ReplaceAll(string(m[1]), ",", ""))
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
@@ -0,0 +1,140 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubdependents
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+)
+
+// testPage is a trimmed down dependents page.
+const testPage = `<div class="table-list-header-toggle">
+  <a class="btn-link selected" href="/owner/repo/network/dependents?dependent_type=REPOSITORY">
+    <svg class="octicon octicon-code-square" viewBox="0 0 16 16" width="16" height="16"><path d="M0 1.75C0 .784.784 0 1.75 0h12.5C15.216 0 16 .784 16 1.75v12.5A1.75 1.75 0 0 1 14.25 16H1.75A1.75 1.75 0 0 1 0 14.25Z"></path></svg>
+    1,234,567
+    Repositories
+  </a>
+  <a class="btn-link" href="/owner/repo/network/dependents?dependent_type=PACKAGE">
+    <svg class="octicon octicon-package" viewBox="0 0 16 16" width="16" height="16"><path d="m8.878.392 5.25 3.045c.54.314.872.89.872 1.514v6.098a1.75 1.75 0 0 1-.872 1.514Z"></path></svg>
+    8,901
+    Packages
+  </a>
+</div>`
+
+type testRepo struct {
+	u *url.URL
+}
+
+func (r *testRepo) URL() *url.URL {
+	return r.u
+}
+
+func newTestSource(t *testing.T) *Source {
+	t.Helper()
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/owner/repo/network/dependents":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte(testPage))
+		case "/owner/empty/network/dependents":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte("<p>We haven't found any dependents for this repository yet.</p>"))
+		case "/owner/broken/network/dependents":
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return NewSource(zaptest.NewLogger(t), s.Client(), s.URL+"/").(*Source)
+}
+
+func TestSource(t *testing.T) {
+	src := newTestSource(t)
+	u, _ := url.Parse("https://github.com/owner/repo")
+	r := &testRepo{u: u}
+	if !src.IsSupported(r) {
+		t.Fatal("IsSupported() = false, want true")
+	}
+	set, err := src.Get(context.Background(), r, "")
+	if err != nil {
+		t.Fatalf("Get() = %v, want no error", err)
+	}
+	s := set.(*dependentsSet)
+	if got, want := s.RepositoryCount.Get(), 1234567; got != want {
+		t.Errorf("RepositoryCount = %d, want %d", got, want)
+	}
+	if got, want := s.PackageCount.Get(), 8901; got != want {
+		t.Errorf("PackageCount = %d, want %d", got, want)
+	}
+}
+
+func TestSource_Unset(t *testing.T) {
+	src := newTestSource(t)
+	for _, rawURL := range []string{
+		"https://github.com/owner/empty",
+		"https://github.com/owner/missing",
+	} {
+		t.Run(rawURL, func(t *testing.T) {
+			u, _ := url.Parse(rawURL)
+			set, err := src.Get(context.Background(), &testRepo{u: u}, "")
+			if err != nil {
+				t.Fatalf("Get() = %v, want no error", err)
+			}
+			s := set.(*dependentsSet)
+			if s.RepositoryCount.IsSet() {
+				t.Errorf("RepositoryCount is set, want unset")
+			}
+			if s.PackageCount.IsSet() {
+				t.Errorf("PackageCount is set, want unset")
+			}
+		})
+	}
+}
+
+func TestSource_Error(t *testing.T) {
+	src := newTestSource(t)
+	u, _ := url.Parse("https://github.com/owner/broken")
+	if _, err := src.Get(context.Background(), &testRepo{u: u}, ""); err == nil {
+		t.Fatal("Get() = no error, want error")
+	}
+}
+
+func TestIsSupported(t *testing.T) {
+	src := newTestSource(t)
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{url: "https://github.com/owner/repo", want: true},
+		{url: "https://GitHub.com/owner/repo.git", want: true},
+		{url: "https://github.com/owner", want: false},
+		{url: "https://github.com/owner/repo/tree/main", want: false},
+		{url: "https://gitlab.com/owner/repo", want: false},
+	}
+	for _, test := range tests {
+		t.Run(test.url, func(t *testing.T) {
+			u, _ := url.Parse(test.url)
+			if got := src.IsSupported(&testRepo{u: u}); got != test.want {
+				t.Errorf("IsSupported() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
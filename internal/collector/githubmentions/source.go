@@ -34,7 +34,7 @@ import (
 )
 
 type mentionSet struct {
-	MentionCount signal.Field[int] `signal:"github_mention_count,legacy"`
+	MentionCount signal.Field[int] `signal:"github_mention_count,legacy" desc:"Number of commits on GitHub that mention the repository in their message." unit:"count" source:"GitHub search API"`
 }
 
 func (s *mentionSet) Namespace() signal.Namespace {
@@ -0,0 +1,180 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// projectData contains the fields of a GitLab project that are used for
+// collecting signals.
+type projectData struct {
+	ID            int
+	WebURL        string `json:"web_url"`
+	DefaultBranch string `json:"default_branch"`
+
+	License *struct{ Name string } `json:"license"`
+
+	CreatedAt      time.Time `json:"created_at"`
+	LastActivityAt time.Time `json:"last_activity_at"`
+
+	StarCount  int `json:"star_count"`
+	ForksCount int `json:"forks_count"`
+
+	Archived      bool
+	EmptyRepo     bool `json:"empty_repo"`
+	IssuesEnabled bool `json:"issues_enabled"`
+
+	// ForkedFromProject is only present if the project is a fork.
+	ForkedFromProject *struct {
+		WebURL string `json:"web_url"`
+	} `json:"forked_from_project"`
+}
+
+type commitData struct {
+	AuthoredDate  time.Time `json:"authored_date"`
+	CommittedDate time.Time `json:"committed_date"`
+}
+
+// repo implements the projectrepo.Repo interface for a GitLab project.
+type repo struct {
+	client  *client
+	origURL *url.URL
+	logger  *zap.Logger
+
+	BasicData *projectData
+	realURL   *url.URL
+	created   time.Time
+	updated   time.Time
+}
+
+// URL implements the projectrepo.Repo interface.
+func (r *repo) URL() *url.URL {
+	return r.realURL
+}
+
+func (r *repo) init(ctx context.Context) error {
+	if r.BasicData != nil {
+		// Already finished. Don't init() more than once.
+		return nil
+	}
+	r.logger.Debug("Fetching basic data from GitLab")
+	data := &projectData{}
+	q := url.Values{"license": {"true"}}
+	if _, err := r.client.get(ctx, "projects/"+url.PathEscape(projectFullPath(r.origURL)), q, data); err != nil {
+		return err
+	}
+	var err error
+	r.realURL, err = url.Parse(data.WebURL)
+	if err != nil {
+		return fmt.Errorf("parse web_url: %w", err)
+	}
+	r.created = data.CreatedAt
+	r.updated = data.LastActivityAt
+	if !data.EmptyRepo {
+		r.logger.Debug("Fetching commit times")
+		if err := r.initCommitTimes(ctx, data); err != nil {
+			return err
+		}
+	}
+	// Set BasicData last as it is used to indicate init() has been called.
+	r.BasicData = data
+	return nil
+}
+
+// initCommitTimes determines the created and updated times of the project
+// based on its commit history.
+//
+// Projects that are imported or mirrored may have commits that pre-date the
+// creation of the project on GitLab.
+func (r *repo) initCommitTimes(ctx context.Context, data *projectData) error {
+	path := projectPath(data.ID) + "/repository/commits"
+
+	var latest []commitData
+	q := url.Values{"per_page": {"1"}}
+	if data.DefaultBranch != "" {
+		q.Set("ref_name", data.DefaultBranch)
+	}
+	if _, err := r.client.get(ctx, path, q, &latest); err != nil {
+		return fmt.Errorf("fetch latest commit: %w", err)
+	}
+	if len(latest) > 0 {
+		r.updated = latest[0].AuthoredDate
+	}
+
+	// Commits are returned newest first, so the last page contains the
+	// earliest commit.
+	q.Set("until", data.CreatedAt.Format(time.RFC3339))
+	total, err := r.client.count(ctx, path, q, maxExactTotal)
+	if err != nil {
+		return fmt.Errorf("count commits: %w", err)
+	}
+	if total == 0 || total == maxExactTotal {
+		// Either there are no earlier commits, or there are too many for
+		// GitLab to report the exact number.
+		return nil
+	}
+	var earliest []commitData
+	q.Set("page", fmt.Sprint(total))
+	if _, err := r.client.get(ctx, path, q, &earliest); err != nil {
+		return fmt.Errorf("fetch earliest commit: %w", err)
+	}
+	if len(earliest) > 0 && earliest[0].CommittedDate.Before(r.created) {
+		r.created = earliest[0].CommittedDate
+	}
+	return nil
+}
+
+// ParentURL implements the projectrepo.Fork interface.
+func (r *repo) ParentURL() *url.URL {
+	if r.BasicData.ForkedFromProject == nil {
+		return nil
+	}
+	u, err := url.Parse(r.BasicData.ForkedFromProject.WebURL)
+	if err != nil {
+		return nil
+	}
+	return u
+}
+
+func (r *repo) path() string {
+	return projectPath(r.BasicData.ID)
+}
+
+func (r *repo) createdAt() time.Time {
+	return r.created
+}
+
+func (r *repo) updatedAt() time.Time {
+	return r.updated
+}
+
+// projectFullPath returns the full path of the project, including any
+// namespaces and subgroups, for the project URL u.
+func projectFullPath(u *url.URL) string {
+	p := strings.Trim(u.Path, "/")
+	// Strip the path to any resource within the project, such as
+	// "/-/tree/main".
+	if i := strings.Index(p, "/-/"); i >= 0 {
+		p = p[:i]
+	}
+	return strings.TrimSuffix(p, ".git")
+}
@@ -0,0 +1,264 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gitlab provides a projectrepo.Factory and signal Sources for
+// projects hosted on gitlab.com and self-hosted GitLab instances.
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/ossf/criticality_score/v2/internal/collector/github/legacy"
+	"github.com/ossf/criticality_score/v2/internal/collector/projectrepo"
+	"github.com/ossf/criticality_score/v2/internal/collector/signal"
+)
+
+const (
+	legacyReleaseLookbackDays = 365
+	legacyReleaseLookback     = legacyReleaseLookbackDays * 24 * time.Hour
+	legacyCommitLookback      = 365 * 24 * time.Hour
+
+	maxCommitsLimit = maxExactTotal
+	releasesPerPage = 100
+)
+
+type RepoSource struct{}
+
+func (rc *RepoSource) EmptySet() signal.Set {
+	return &signal.RepoSet{}
+}
+
+func (rc *RepoSource) Get(ctx context.Context, r projectrepo.Repo, _ string) (signal.Set, error) {
+	glr, ok := r.(*repo)
+	if !ok {
+		return nil, errors.New("project is not a gitlab project")
+	}
+	now := time.Now()
+
+	s := &signal.RepoSet{
+		URL:          signal.Val(r.URL().String()),
+		StarCount:    signal.Val(glr.BasicData.StarCount),
+		ForkCount:    signal.Val(glr.BasicData.ForksCount),
+		CreatedAt:    signal.Val(glr.createdAt()),
+		CreatedSince: signal.Val(legacy.TimeDelta(now, glr.createdAt(), legacy.SinceDuration)),
+		UpdatedAt:    signal.Val(glr.updatedAt()),
+		UpdatedSince: signal.Val(legacy.TimeDelta(now, glr.updatedAt(), legacy.SinceDuration)),
+
+		IsArchived:       signal.Val(glr.BasicData.Archived),
+		IsEmpty:          signal.Val(glr.BasicData.EmptyRepo),
+		HasIssuesEnabled: signal.Val(glr.BasicData.IssuesEnabled),
+		IsFork:           signal.Val(glr.BasicData.ForkedFromProject != nil),
+	}
+	if glr.BasicData.ForkedFromProject != nil {
+		s.ParentURL.Set(glr.BasicData.ForkedFromProject.WebURL)
+	}
+	if glr.BasicData.License != nil {
+		s.License.Set(glr.BasicData.License.Name)
+	}
+
+	glr.logger.Debug("Fetching languages")
+	var languages map[string]float64
+	if _, err := glr.client.get(ctx, glr.path()+"/languages", nil, &languages); err != nil {
+		return nil, fmt.Errorf("fetch languages: %w", err)
+	}
+	s.Language.Set(primaryLanguage(languages))
+
+	glr.logger.Debug("Fetching contributors")
+	contributors, err := glr.client.count(ctx, glr.path()+"/repository/contributors", nil, legacy.MaxContributorLimit)
+	if err != nil {
+		return nil, fmt.Errorf("count contributors: %w", err)
+	}
+	s.ContributorCount.Set(contributors)
+
+	glr.logger.Debug("Fetching recent commits")
+	q := url.Values{"since": {now.Add(-legacyCommitLookback).UTC().Format(time.RFC3339)}}
+	commits, err := glr.client.count(ctx, glr.path()+"/repository/commits", q, maxCommitsLimit)
+	if err != nil {
+		return nil, fmt.Errorf("count commits: %w", err)
+	}
+	s.CommitFrequency.Set(legacy.Round(float64(commits)/52, 2))
+
+	glr.logger.Debug("Fetching releases")
+	releaseCount, err := glr.recentReleaseCount(ctx, now.Add(-legacyReleaseLookback))
+	if err != nil {
+		return nil, fmt.Errorf("count releases: %w", err)
+	}
+	if releaseCount != 0 {
+		s.RecentReleaseCount.Set(releaseCount)
+	} else {
+		// Fallback to estimating the number of releases from the tags, in the
+		// same way the GitHub source does.
+		tags, err := glr.client.count(ctx, glr.path()+"/repository/tags", nil, maxExactTotal)
+		if err != nil {
+			return nil, fmt.Errorf("count tags: %w", err)
+		}
+		daysSinceCreated := int(now.Sub(glr.createdAt()).Hours()) / 24
+		if daysSinceCreated > 0 {
+			s.RecentReleaseCount.Set((tags * legacyReleaseLookbackDays) / daysSinceCreated)
+		} else {
+			s.RecentReleaseCount.Set(0)
+		}
+	}
+	return s, nil
+}
+
+func (rc *RepoSource) IsSupported(p projectrepo.Repo) bool {
+	_, ok := p.(*repo)
+	return ok
+}
+
+// recentReleaseCount returns the number of releases made after cutoff.
+func (r *repo) recentReleaseCount(ctx context.Context, cutoff time.Time) (int, error) {
+	q := url.Values{
+		"order_by": {"released_at"},
+		"sort":     {"desc"},
+		"per_page": {fmt.Sprint(releasesPerPage)},
+	}
+	total := 0
+	for page := 1; ; page++ {
+		q.Set("page", fmt.Sprint(page))
+		var releases []struct {
+			ReleasedAt time.Time `json:"released_at"`
+		}
+		resp, err := r.client.get(ctx, r.path()+"/releases", q, &releases)
+		if err != nil {
+			return 0, err
+		}
+		for _, rel := range releases {
+			if rel.ReleasedAt.Before(cutoff) {
+				return total, nil
+			}
+			total++
+		}
+		if resp.Header.Get(nextPageHeader) == "" {
+			return total, nil
+		}
+	}
+}
+
+// primaryLanguage returns the language with the largest share of the
+// repository.
+func primaryLanguage(languages map[string]float64) string {
+	name := ""
+	share := 0.0
+	for l, s := range languages {
+		if s > share || (s == share && l < name) {
+			name = l
+			share = s
+		}
+	}
+	return name
+}
+
+type IssuesSource struct{}
+
+func (ic *IssuesSource) EmptySet() signal.Set {
+	return &signal.IssuesSet{}
+}
+
+func (ic *IssuesSource) Get(ctx context.Context, r projectrepo.Repo, _ string) (signal.Set, error) {
+	glr, ok := r.(*repo)
+	if !ok {
+		return nil, errors.New("project is not a gitlab project")
+	}
+	s := &signal.IssuesSet{}
+	since := time.Now().UTC().Add(-legacy.IssueLookback).Format(time.RFC3339)
+
+	// The GitHub sources count pull requests as issues, so merge requests are
+	// counted here to remain consistent.
+	glr.logger.Debug("Fetching closed issues")
+	closed := 0
+	for _, c := range []struct{ path, state string }{
+		{"/issues", "closed"},
+		{"/merge_requests", "closed"},
+		{"/merge_requests", "merged"},
+	} {
+		q := url.Values{"state": {c.state}, "updated_after": {since}}
+		n, err := glr.client.count(ctx, glr.path()+c.path, q, legacy.MaxIssuesLimit)
+		if err != nil {
+			return nil, fmt.Errorf("count closed issues: %w", err)
+		}
+		closed += n
+	}
+	s.ClosedCount.Set(min(closed, legacy.MaxIssuesLimit))
+
+	glr.logger.Debug("Fetching updated issues")
+	up := 0
+	for _, path := range []string{"/issues", "/merge_requests"} {
+		q := url.Values{"scope": {"all"}, "updated_after": {since}}
+		n, err := glr.client.count(ctx, glr.path()+path, q, legacy.MaxIssuesLimit)
+		if err != nil {
+			return nil, fmt.Errorf("count updated issues: %w", err)
+		}
+		up += n
+	}
+	s.UpdatedCount.Set(min(up, legacy.MaxIssuesLimit))
+
+	if up == 0 {
+		s.CommentFrequency.Set(0)
+		return s, nil
+	}
+
+	glr.logger.Debug("Fetching comment frequency")
+	comments, err := glr.commentCount(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("count comments: %w", err)
+	}
+	s.CommentFrequency.Set(legacy.Round(float64(comments)/float64(up), 2))
+	return s, nil
+}
+
+func (ic *IssuesSource) IsSupported(r projectrepo.Repo) bool {
+	_, ok := r.(*repo)
+	return ok
+}
+
+// commentCount returns the number of comments on the issues and merge
+// requests updated since the given time.
+//
+// Unlike GitHub, GitLab does not support listing all the comments in a
+// project, so the count is the total number of comments on each issue and
+// merge request, rather than only the comments made since the given time.
+func (r *repo) commentCount(ctx context.Context, since string) (int, error) {
+	total := 0
+	for _, path := range []string{"/issues", "/merge_requests"} {
+		q := url.Values{
+			"scope":         {"all"},
+			"updated_after": {since},
+			"per_page":      {"100"},
+		}
+		for page, seen := 1, 0; seen < legacy.MaxIssuesLimit; page++ {
+			q.Set("page", fmt.Sprint(page))
+			var items []struct {
+				UserNotesCount int `json:"user_notes_count"`
+			}
+			resp, err := r.client.get(ctx, r.path()+path, q, &items)
+			if err != nil {
+				return 0, err
+			}
+			for _, i := range items {
+				total += i.UserNotesCount
+			}
+			seen += len(items)
+			if resp.Header.Get(nextPageHeader) == "" {
+				break
+			}
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,241 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+
+	"github.com/ossf/criticality_score/v2/internal/collector/projectrepo"
+	"github.com/ossf/criticality_score/v2/internal/collector/signal"
+)
+
+// fakeGitLab is a minimal stand-in for the GitLab REST API serving a single
+// project.
+type fakeGitLab struct {
+	*httptest.Server
+	project map[string]any
+	created time.Time
+}
+
+func newFakeGitLab(t *testing.T) *fakeGitLab {
+	t.Helper()
+	f := &fakeGitLab{
+		created: time.Now().UTC().Add(-400 * 24 * time.Hour).Truncate(time.Second),
+	}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.serve))
+	t.Cleanup(f.Close)
+	f.project = map[string]any{
+		"id":             42,
+		"web_url":        f.URL + "/group/sub/project",
+		"default_branch": "main",
+		"license":        map[string]any{"name": "MIT License"},
+		"created_at":     f.created,
+		"star_count":     12,
+		"forks_count":    3,
+		"empty_repo":     false,
+		"forked_from_project": map[string]any{
+			"web_url": f.URL + "/upstream/project",
+		},
+	}
+	return f
+}
+
+func (f *fakeGitLab) serve(w http.ResponseWriter, r *http.Request) {
+	write := func(v any, total int) {
+		if total >= 0 {
+			w.Header().Set(totalHeader, fmt.Sprint(total))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(v)
+	}
+	q := r.URL.Query()
+	switch r.URL.EscapedPath() {
+	case "/api/v4/projects/group%2Fsub%2Fproject":
+		write(f.project, -1)
+	case "/api/v4/projects/42/languages":
+		write(map[string]float64{"Go": 80.5, "Shell": 19.5}, -1)
+	case "/api/v4/projects/42/repository/contributors":
+		write([]any{map[string]any{}}, 7)
+	case "/api/v4/projects/42/repository/commits":
+		switch {
+		case q.Get("until") != "" && q.Get("page") == "3":
+			write([]any{map[string]any{"committed_date": f.created.Add(-24 * time.Hour)}}, 3)
+		case q.Get("until") != "":
+			write([]any{map[string]any{}}, 3)
+		case q.Get("since") != "":
+			write([]any{map[string]any{}}, 104)
+		default:
+			write([]any{map[string]any{"authored_date": time.Now().UTC().Add(-48 * time.Hour)}}, 500)
+		}
+	case "/api/v4/projects/42/releases":
+		write([]any{
+			map[string]any{"released_at": time.Now().UTC().Add(-24 * time.Hour)},
+			map[string]any{"released_at": time.Now().UTC().Add(-48 * time.Hour)},
+			map[string]any{"released_at": time.Now().UTC().Add(-500 * 24 * time.Hour)},
+		}, 3)
+	case "/api/v4/projects/42/issues":
+		switch q.Get("state") {
+		case "closed":
+			write([]any{map[string]any{}}, 4)
+		default:
+			write([]any{map[string]any{"user_notes_count": 3}, map[string]any{"user_notes_count": 1}}, 6)
+		}
+	case "/api/v4/projects/42/merge_requests":
+		switch q.Get("state") {
+		case "closed":
+			write([]any{map[string]any{}}, 1)
+		case "merged":
+			write([]any{map[string]any{}}, 2)
+		default:
+			write([]any{map[string]any{"user_notes_count": 4}}, 4)
+		}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (f *fakeGitLab) factory(t *testing.T) projectrepo.Factory {
+	t.Helper()
+	u, _ := url.Parse(f.URL)
+	return NewRepoFactory(f.Client(), zaptest.NewLogger(t), []string{u.Hostname()})
+}
+
+func (f *fakeGitLab) repo(t *testing.T) projectrepo.Repo {
+	t.Helper()
+	u, _ := url.Parse(f.URL + "/group/sub/project.git")
+	r, err := f.factory(t).New(context.Background(), u)
+	if err != nil {
+		t.Fatalf("New() = %v, want no error", err)
+	}
+	return r
+}
+
+func TestFactoryMatch(t *testing.T) {
+	f := NewRepoFactory(http.DefaultClient, zaptest.NewLogger(t), []string{"gitlab.com", "GitLab.Example.com"})
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{url: "https://gitlab.com/group/project", want: true},
+		{url: "https://gitlab.com/group/sub/project/-/tree/main", want: true},
+		{url: "https://gitlab.example.com/group/project", want: true},
+		{url: "https://gitlab.com/group", want: false},
+		{url: "https://github.com/owner/repo", want: false},
+	}
+	for _, test := range tests {
+		t.Run(test.url, func(t *testing.T) {
+			u, _ := url.Parse(test.url)
+			if got := f.Match(u); got != test.want {
+				t.Fatalf("Match(%s) = %v, want %v", test.url, got, test.want)
+			}
+		})
+	}
+}
+
+func TestFactoryNew_NotFound(t *testing.T) {
+	f := newFakeGitLab(t)
+	u, _ := url.Parse(f.URL + "/group/missing")
+	_, err := f.factory(t).New(context.Background(), u)
+	if !errors.Is(err, projectrepo.ErrNoRepoFound) {
+		t.Fatalf("New() = %v, want %v", err, projectrepo.ErrNoRepoFound)
+	}
+}
+
+func TestRepoSource(t *testing.T) {
+	f := newFakeGitLab(t)
+	r := f.repo(t)
+	src := &RepoSource{}
+	if !src.IsSupported(r) {
+		t.Fatal("IsSupported() = false, want true")
+	}
+	set, err := src.Get(context.Background(), r, "")
+	if err != nil {
+		t.Fatalf("Get() = %v, want no error", err)
+	}
+	s := set.(*signal.RepoSet)
+
+	if got, want := s.URL.Get(), f.URL+"/group/sub/project"; got != want {
+		t.Errorf("URL = %q, want %q", got, want)
+	}
+	if got, want := s.Language.Get(), "Go"; got != want {
+		t.Errorf("Language = %q, want %q", got, want)
+	}
+	if got, want := s.License.Get(), "MIT License"; got != want {
+		t.Errorf("License = %q, want %q", got, want)
+	}
+	if got, want := s.StarCount.Get(), 12; got != want {
+		t.Errorf("StarCount = %d, want %d", got, want)
+	}
+	if got, want := s.ForkCount.Get(), 3; got != want {
+		t.Errorf("ForkCount = %d, want %d", got, want)
+	}
+	if got, want := s.IsFork.Get(), true; got != want {
+		t.Errorf("IsFork = %v, want %v", got, want)
+	}
+	if got, want := s.ParentURL.Get(), f.URL+"/upstream/project"; got != want {
+		t.Errorf("ParentURL = %q, want %q", got, want)
+	}
+	if got, want := r.(projectrepo.Fork).ParentURL().String(), f.URL+"/upstream/project"; got != want {
+		t.Errorf("ParentURL() = %q, want %q", got, want)
+	}
+	if got, want := s.CreatedAt.Get(), f.created.Add(-24*time.Hour); !got.Equal(want) {
+		t.Errorf("CreatedAt = %v, want %v", got, want)
+	}
+	if got, want := s.UpdatedSince.Get(), 0; got != want {
+		t.Errorf("UpdatedSince = %d, want %d", got, want)
+	}
+	if got, want := s.ContributorCount.Get(), 7; got != want {
+		t.Errorf("ContributorCount = %d, want %d", got, want)
+	}
+	if got, want := s.CommitFrequency.Get(), 2.0; got != want {
+		t.Errorf("CommitFrequency = %v, want %v", got, want)
+	}
+	if got, want := s.RecentReleaseCount.Get(), 2; got != want {
+		t.Errorf("RecentReleaseCount = %d, want %d", got, want)
+	}
+	if s.OrgCount.IsSet() {
+		t.Errorf("OrgCount is set, want unset")
+	}
+}
+
+func TestIssuesSource(t *testing.T) {
+	f := newFakeGitLab(t)
+	r := f.repo(t)
+	set, err := (&IssuesSource{}).Get(context.Background(), r, "")
+	if err != nil {
+		t.Fatalf("Get() = %v, want no error", err)
+	}
+	s := set.(*signal.IssuesSet)
+
+	if got, want := s.ClosedCount.Get(), 7; got != want {
+		t.Errorf("ClosedCount = %d, want %d", got, want)
+	}
+	if got, want := s.UpdatedCount.Get(), 10; got != want {
+		t.Errorf("UpdatedCount = %d, want %d", got, want)
+	}
+	if got, want := s.CommentFrequency.Get(), 0.8; got != want {
+		t.Errorf("CommentFrequency = %v, want %v", got, want)
+	}
+}
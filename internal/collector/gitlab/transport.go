@@ -0,0 +1,100 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitlab
+
+import (
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ossf/criticality_score/v2/internal/retry"
+)
+
+const (
+	tokenHeader = "PRIVATE-TOKEN"
+
+	// defaultTokenHost is the only host that is sent the token found in
+	// tokenEnvVars.
+	defaultTokenHost = "gitlab.com"
+
+	// hostTokensEnvVar is the environment variable checked for tokens for
+	// specific hosts, as a comma separated list of "host=token" pairs.
+	hostTokensEnvVar = "GITLAB_HOST_TOKENS"
+)
+
+// tokenEnvVars lists the environment variables that are checked, in order, for
+// a gitlab.com access token.
+var tokenEnvVars = []string{"GITLAB_AUTH_TOKEN", "GITLAB_TOKEN"}
+
+// NewTransport returns an http.RoundTripper for communicating with GitLab's
+// API.
+//
+// Requests are authenticated with an access token if one is set in the
+// environment for the host being requested, and are retried if they are rate
+// limited or fail with a server error.
+func NewTransport(inner http.RoundTripper) http.RoundTripper {
+	if tokens := tokensFromEnv(); len(tokens) > 0 {
+		inner = &tokenRoundTripper{inner: inner, tokens: tokens}
+	}
+	return retry.NewRoundTripper(inner,
+		retry.InitialDelay(time.Minute),
+		retry.RetryAfter(retry.RetryAfterSeconds),
+		retry.Strategy(retry.TooManyRequests),
+		retry.Strategy(retry.ServerError),
+	)
+}
+
+// tokensFromEnv returns the access tokens set in the environment, keyed by
+// the lowercase hostname they may be sent to.
+//
+// A token in tokenEnvVars is only used for defaultTokenHost, so that it is
+// never sent to a self-hosted instance. Tokens for other hosts, or to override
+// the token for defaultTokenHost, are read from hostTokensEnvVar.
+func tokensFromEnv() map[string]string {
+	tokens := make(map[string]string)
+	for _, name := range tokenEnvVars {
+		if token := os.Getenv(name); token != "" {
+			tokens[defaultTokenHost] = token
+			break
+		}
+	}
+	for _, pair := range strings.Split(os.Getenv(hostTokensEnvVar), ",") {
+		host, token, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || host == "" || token == "" {
+			continue
+		}
+		tokens[strings.ToLower(host)] = token
+	}
+	return tokens
+}
+
+// tokenRoundTripper adds a GitLab access token to each request sent to a host
+// that has a token.
+type tokenRoundTripper struct {
+	inner  http.RoundTripper
+	tokens map[string]string
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (rt *tokenRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	token, ok := rt.tokens[strings.ToLower(r.URL.Hostname())]
+	if !ok {
+		return rt.inner.RoundTrip(r)
+	}
+	r = r.Clone(r.Context())
+	r.Header.Set(tokenHeader, token)
+	return rt.inner.RoundTrip(r)
+}
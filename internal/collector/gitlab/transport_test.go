@@ -0,0 +1,95 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitlab
+
+import (
+	"net/http"
+	"testing"
+)
+
+// headerRecorder is an http.RoundTripper that records the token header of the
+// last request.
+type headerRecorder struct {
+	token string
+}
+
+func (rt *headerRecorder) RoundTrip(r *http.Request) (*http.Response, error) {
+	rt.token = r.Header.Get(tokenHeader)
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: r}, nil
+}
+
+func TestNewTransport_Tokens(t *testing.T) {
+	//nolint:govet
+	tests := []struct {
+		name       string
+		token      string
+		hostTokens string
+		url        string
+		want       string
+	}{
+		{
+			name:  "default token sent to gitlab.com",
+			token: "abc",
+			url:   "https://gitlab.com/api/v4/projects/1",
+			want:  "abc",
+		},
+		{
+			name:  "default token not sent to other hosts",
+			token: "abc",
+			url:   "https://gitlab.example.com/api/v4/projects/1",
+			want:  "",
+		},
+		{
+			name:       "host token sent to its host",
+			token:      "abc",
+			hostTokens: "gitlab.example.com=def, other.example.com=ghi",
+			url:        "https://GitLab.example.com/api/v4/projects/1",
+			want:       "def",
+		},
+		{
+			name:       "host token overrides default token",
+			token:      "abc",
+			hostTokens: "gitlab.com=def",
+			url:        "https://gitlab.com/api/v4/projects/1",
+			want:       "def",
+		},
+		{
+			name:       "malformed host tokens ignored",
+			hostTokens: "gitlab.example.com,=abc,other.example.com=",
+			url:        "https://gitlab.example.com/api/v4/projects/1",
+			want:       "",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("GITLAB_AUTH_TOKEN", test.token)
+			t.Setenv("GITLAB_TOKEN", "")
+			t.Setenv(hostTokensEnvVar, test.hostTokens)
+			rec := &headerRecorder{}
+			req, err := http.NewRequest(http.MethodGet, test.url, nil)
+			if err != nil {
+				t.Fatalf("NewRequest() = %v, want no error", err)
+			}
+			resp, err := NewTransport(rec).RoundTrip(req)
+			if err != nil {
+				t.Fatalf("RoundTrip() = %v, want no error", err)
+			}
+			resp.Body.Close()
+			if rec.token != test.want {
+				t.Errorf("%s header = %q, want %q", tokenHeader, rec.token, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package osv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ossf/criticality_score/v2/internal/retry"
+)
+
+// DefaultAPIURL is the base URL of the public OSV API.
+const DefaultAPIURL = "https://api.osv.dev/v1"
+
+// vulnLister is used to find the vulnerabilities affecting a package.
+type vulnLister interface {
+	Vulns(ctx context.Context, pkg Package) ([]*Vuln, error)
+}
+
+// apiClient is a vulnLister backed by an OSV-compatible API.
+type apiClient struct {
+	http    *http.Client
+	baseURL string
+}
+
+// Vulns implements the vulnLister interface.
+func (c *apiClient) Vulns(ctx context.Context, pkg Package) ([]*Vuln, error) {
+	var vulns []*Vuln
+	token := ""
+	for {
+		var resp struct {
+			Vulns         []*Vuln `json:"vulns"`
+			NextPageToken string  `json:"next_page_token"`
+		}
+		req := struct {
+			Package   Package `json:"package"`
+			PageToken string  `json:"page_token,omitempty"`
+		}{pkg, token}
+		if err := c.post(ctx, "query", req, &resp); err != nil {
+			return nil, err
+		}
+		vulns = append(vulns, resp.Vulns...)
+		if resp.NextPageToken == "" {
+			return vulns, nil
+		}
+		token = resp.NextPageToken
+	}
+}
+
+// post sends body as JSON to the API endpoint at path and parses the JSON
+// response into v.
+func (c *apiClient) post(ctx context.Context, path string, body, v any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("marshal request: %w", err)
+	}
+	u := strings.TrimSuffix(c.baseURL, "/") + "/" + path
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("new request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("osv request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || 300 <= resp.StatusCode {
+		return fmt.Errorf("osv request %s: unexpected status %s", u, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("reading response for %s: %w", u, err)
+	}
+	return nil
+}
+
+// NewTransport returns an http.RoundTripper for communicating with the OSV
+// API.
+//
+// Requests are retried if they are rate limited or fail with a server error.
+func NewTransport(inner http.RoundTripper) http.RoundTripper {
+	return retry.NewRoundTripper(inner,
+		retry.InitialDelay(time.Minute),
+		retry.RetryAfter(retry.RetryAfterSeconds),
+		retry.Strategy(retry.TooManyRequests),
+		retry.Strategy(retry.ServerError),
+	)
+}
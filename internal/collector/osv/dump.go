@@ -0,0 +1,86 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package osv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// dumpDir is a vulnLister backed by a local directory containing OSV
+// vulnerability entries, such as the extracted contents of the all.zip files
+// published for each ecosystem.
+//
+// Every file ending in ".json" below the directory is read the first time
+// Vulns is called.
+type dumpDir struct {
+	dir string
+
+	once  sync.Once
+	err   error
+	index map[string][]*Vuln
+}
+
+// Vulns implements the vulnLister interface.
+func (d *dumpDir) Vulns(_ context.Context, pkg Package) ([]*Vuln, error) {
+	d.once.Do(func() {
+		d.index, d.err = loadDumpDir(d.dir)
+	})
+	if d.err != nil {
+		return nil, d.err
+	}
+	return d.index[pkg.key()], nil
+}
+
+// loadDumpDir reads every vulnerability entry in dir and indexes them by the
+// packages they affect.
+func loadDumpDir(dir string) (map[string][]*Vuln, error) {
+	index := make(map[string][]*Vuln)
+	err := filepath.WalkDir(dir, func(path string, e fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if e.IsDir() || !strings.HasSuffix(path, ".json") {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		v := &Vuln{}
+		if err := json.Unmarshal(data, v); err != nil {
+			return fmt.Errorf("parse %s: %w", path, err)
+		}
+		seen := make(map[string]bool)
+		for _, a := range v.Affected {
+			k := a.Package.key()
+			if !seen[k] {
+				seen[k] = true
+				index[k] = append(index[k], v)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("load osv dump: %w", err)
+	}
+	return index, nil
+}
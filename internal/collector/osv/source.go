@@ -0,0 +1,236 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package osv provides a signal Source for the history of vulnerabilities
+// affecting the packages built from a repository, using data from OSV.
+//
+// The packages built from a repository are found using deps.dev, so only
+// repositories known to deps.dev are supported.
+package osv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ossf/criticality_score/v2/internal/collector/github/legacy"
+	"github.com/ossf/criticality_score/v2/internal/collector/projectrepo"
+	"github.com/ossf/criticality_score/v2/internal/collector/signal"
+	"github.com/ossf/criticality_score/v2/internal/depsdevapi"
+)
+
+// DefaultLookback is the default period used for counting recent
+// vulnerabilities.
+const DefaultLookback = 5 * 365 * 24 * time.Hour
+
+// ecosystems maps deps.dev package systems to OSV ecosystems.
+var ecosystems = map[string]string{
+	"CARGO":    "crates.io",
+	"GO":       "Go",
+	"MAVEN":    "Maven",
+	"NPM":      "npm",
+	"NUGET":    "NuGet",
+	"PYPI":     "PyPI",
+	"RUBYGEMS": "RubyGems",
+}
+
+type vulnsSet struct {
+	// RecentCount is the number of vulnerabilities published during the
+	// lookback period.
+	RecentCount signal.Field[int] `desc:"Number of vulnerabilities published." unit:"count" lookback:"5 years by default" source:"OSV"`
+
+	// UnfixedCount is the number of vulnerabilities that have at least one
+	// range of affected versions without a fix.
+	UnfixedCount signal.Field[int] `desc:"Number of vulnerabilities without a fix for every affected range." unit:"count" source:"OSV"`
+
+	// MedianDaysToFix is the median number of days between a vulnerability
+	// being published and the first version containing a fix being released.
+	// Fixes released before the vulnerability was published count as 0 days.
+	MedianDaysToFix signal.Field[float64] `desc:"Median time between a vulnerability being published and a fix being released." unit:"days" source:"OSV"`
+}
+
+func (s *vulnsSet) Namespace() signal.Namespace {
+	return signal.Namespace("vulns")
+}
+
+type Source struct {
+	logger   *zap.Logger
+	depsDev  *depsdevapi.Client
+	vulns    vulnLister
+	lookback time.Duration
+}
+
+// NewSource creates a new Source that queries the OSV-compatible API at
+// apiURL for vulnerabilities.
+//
+// Vulnerabilities published in the lookback period are counted as recent.
+func NewSource(logger *zap.Logger, depsDev *depsdevapi.Client, client *http.Client, apiURL string, lookback time.Duration) signal.Source {
+	return &Source{
+		logger:   logger,
+		depsDev:  depsDev,
+		vulns:    &apiClient{http: client, baseURL: apiURL},
+		lookback: lookback,
+	}
+}
+
+// NewDumpSource creates a new Source that reads vulnerabilities from a local
+// directory of OSV entries, rather than an API.
+//
+// Vulnerabilities published in the lookback period are counted as recent.
+func NewDumpSource(logger *zap.Logger, depsDev *depsdevapi.Client, dir string, lookback time.Duration) signal.Source {
+	return &Source{
+		logger:   logger,
+		depsDev:  depsDev,
+		vulns:    &dumpDir{dir: dir},
+		lookback: lookback,
+	}
+}
+
+func (c *Source) EmptySet() signal.Set {
+	return &vulnsSet{}
+}
+
+func (c *Source) IsSupported(r projectrepo.Repo) bool {
+	_, ok := depsdevapi.ProjectID(r.URL())
+	return ok
+}
+
+func (c *Source) Get(ctx context.Context, r projectrepo.Repo, _ string) (signal.Set, error) {
+	s := &vulnsSet{}
+	id, ok := depsdevapi.ProjectID(r.URL())
+	if !ok {
+		return s, nil
+	}
+	logger := c.logger.With(zap.String("url", r.URL().String()))
+
+	logger.Debug("Fetching packages from deps.dev")
+	packages, err := c.packages(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("fetch packages: %w", err)
+	}
+	if len(packages) == 0 {
+		// Without any packages nothing is known about the vulnerabilities.
+		return s, nil
+	}
+
+	logger.Debug("Fetching vulnerabilities")
+	cutoff := time.Now().Add(-c.lookback)
+	seen := make(map[string]bool)
+	recent := 0
+	unfixed := 0
+	var daysToFix []float64
+	for _, key := range packages {
+		pkg := Package{Ecosystem: ecosystems[key.System], Name: key.Name}
+		vulns, err := c.vulns.Vulns(ctx, pkg)
+		if err != nil {
+			return nil, fmt.Errorf("fetch vulnerabilities: %w", err)
+		}
+		var info *depsdevapi.Package
+		for _, v := range vulns {
+			if v.Withdrawn != nil || seenVuln(seen, v) {
+				continue
+			}
+			if v.Published.After(cutoff) {
+				recent++
+			}
+			if v.isUnfixed(pkg) {
+				unfixed++
+			}
+			fixed := v.fixedVersions(pkg)
+			if len(fixed) == 0 {
+				continue
+			}
+			if info == nil {
+				// Only fetch the package's versions when they are needed.
+				if info, err = c.depsDev.Package(ctx, key); errors.Is(err, depsdevapi.ErrNotFound) {
+					info = &depsdevapi.Package{}
+				} else if err != nil {
+					return nil, fmt.Errorf("fetch package: %w", err)
+				}
+			}
+			if t, ok := earliestRelease(info, fixed); ok {
+				daysToFix = append(daysToFix, max(0, t.Sub(v.Published).Hours()/24))
+			}
+		}
+	}
+	s.RecentCount.Set(recent)
+	s.UnfixedCount.Set(unfixed)
+	if len(daysToFix) > 0 {
+		s.MedianDaysToFix.Set(legacy.Round(legacy.Median(daysToFix), 2))
+	}
+	return s, nil
+}
+
+// packages returns the packages in ecosystems supported by OSV that are built
+// from the project with the given deps.dev id, sorted by system and name.
+func (c *Source) packages(ctx context.Context, id string) ([]depsdevapi.PackageKey, error) {
+	versions, err := c.depsDev.ProjectPackageVersions(ctx, id)
+	if errors.Is(err, depsdevapi.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[depsdevapi.PackageKey]bool)
+	var packages []depsdevapi.PackageKey
+	for _, v := range versions {
+		p := depsdevapi.PackageKey{System: v.System, Name: v.Name}
+		if _, ok := ecosystems[p.System]; ok && !seen[p] {
+			seen[p] = true
+			packages = append(packages, p)
+		}
+	}
+	sort.Slice(packages, func(i, j int) bool {
+		if packages[i].System != packages[j].System {
+			return packages[i].System < packages[j].System
+		}
+		return packages[i].Name < packages[j].Name
+	})
+	return packages, nil
+}
+
+// seenVuln returns true if v, or any of its aliases, is already in seen.
+// Otherwise v and its aliases are added to seen.
+//
+// This avoids counting the same vulnerability more than once when it is
+// published by more than one database, such as GHSA and PYSEC.
+func seenVuln(seen map[string]bool, v *Vuln) bool {
+	ids := append([]string{v.ID}, v.Aliases...)
+	for _, id := range ids {
+		if seen[id] {
+			return true
+		}
+	}
+	for _, id := range ids {
+		seen[id] = true
+	}
+	return false
+}
+
+// earliestRelease returns the earliest time any of the versions of the
+// package were published.
+func earliestRelease(p *depsdevapi.Package, versions []string) (t time.Time, ok bool) {
+	for _, v := range versions {
+		if published, found := p.PublishedAt(v); found && (!ok || published.Before(t)) {
+			t = published
+			ok = true
+		}
+	}
+	return t, ok
+}
@@ -0,0 +1,229 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package osv
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+
+	"github.com/ossf/criticality_score/v2/internal/depsdevapi"
+)
+
+type testRepo struct {
+	u *url.URL
+}
+
+func (r *testRepo) URL() *url.URL {
+	return r.u
+}
+
+// testData holds the packages and vulnerabilities served by the fake deps.dev
+// and OSV APIs.
+type testData struct {
+	now   time.Time
+	vulns map[string][]*Vuln
+}
+
+func newTestData() *testData {
+	now := time.Now().UTC().Truncate(time.Second)
+	d := &testData{now: now}
+	npm := Package{Ecosystem: "npm", Name: "pkg"}
+	pypi := Package{Ecosystem: "PyPI", Name: "pkg"}
+	affected := func(p Package, events ...Event) []Affected {
+		return []Affected{{Package: p, Ranges: []Range{{Type: "SEMVER", Events: events}}}}
+	}
+	withdrawn := now.Add(-24 * time.Hour)
+	d.vulns = map[string][]*Vuln{
+		npm.key(): {
+			{
+				ID:        "GHSA-1",
+				Aliases:   []string{"CVE-1"},
+				Published: now.Add(-100 * 24 * time.Hour),
+				Affected:  affected(npm, Event{Introduced: "0"}, Event{Fixed: "1.1.0"}),
+			},
+			{
+				ID:        "GHSA-2",
+				Published: now.Add(-10 * 365 * 24 * time.Hour),
+				Affected:  affected(npm, Event{Introduced: "0"}, Event{Fixed: "2.0.0"}),
+			},
+			{
+				ID:        "GHSA-3",
+				Published: now.Add(-5 * 24 * time.Hour),
+				Affected:  affected(npm, Event{Introduced: "2.0.0"}),
+			},
+			{
+				ID:        "GHSA-4",
+				Published: now.Add(-5 * 24 * time.Hour),
+				Withdrawn: &withdrawn,
+				Affected:  affected(npm, Event{Introduced: "0"}),
+			},
+		},
+		pypi.key(): {
+			{
+				ID:        "PYSEC-1",
+				Aliases:   []string{"CVE-1"},
+				Published: now.Add(-100 * 24 * time.Hour),
+				Affected:  affected(pypi, Event{Introduced: "0"}),
+			},
+		},
+	}
+	return d
+}
+
+func (d *testData) serveDepsDev(w http.ResponseWriter, r *http.Request) {
+	version := func(v string, published time.Time) map[string]any {
+		return map[string]any{
+			"versionKey":  map[string]any{"system": "NPM", "name": "pkg", "version": v},
+			"publishedAt": published,
+		}
+	}
+	var v any
+	switch r.URL.EscapedPath() {
+	case "/v3alpha/projects/github.com%2Fowner%2Frepo:packageversions":
+		v = map[string]any{"versions": []any{
+			map[string]any{"versionKey": map[string]any{"system": "NPM", "name": "pkg", "version": "2.0.0"}},
+			map[string]any{"versionKey": map[string]any{"system": "PYPI", "name": "pkg", "version": "1.0.0"}},
+			map[string]any{"versionKey": map[string]any{"system": "CONAN", "name": "pkg", "version": "1.0.0"}},
+		}}
+	case "/v3alpha/systems/npm/packages/pkg":
+		v = map[string]any{"versions": []any{
+			version("1.0.0", d.now.Add(-11*365*24*time.Hour)),
+			version("1.1.0", d.now.Add(-90*24*time.Hour)),
+			version("2.0.0", d.now.Add(-10*365*24*time.Hour).Add(30*24*time.Hour)),
+		}}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func (d *testData) serveOSV(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.URL.Path != "/v1/query" {
+		http.NotFound(w, r)
+		return
+	}
+	var req struct {
+		Package   Package `json:"package"`
+		PageToken string  `json:"page_token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	// Return a single vulnerability on each page to exercise pagination.
+	vulns := d.vulns[req.Package.key()]
+	resp := map[string]any{}
+	if len(vulns) > 0 {
+		page := 0
+		if req.PageToken != "" {
+			page = len(req.PageToken)
+		}
+		resp["vulns"] = vulns[page : page+1]
+		if page+1 < len(vulns) {
+			resp["next_page_token"] = req.PageToken + "x"
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// writeDump writes the vulnerabilities to a directory in the same layout as
+// the extracted OSV all.zip files.
+func (d *testData) writeDump(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, vulns := range d.vulns {
+		for _, v := range vulns {
+			eco := filepath.Join(dir, v.Affected[0].Package.Ecosystem)
+			if err := os.MkdirAll(eco, 0o755); err != nil {
+				t.Fatal(err)
+			}
+			data, err := json.Marshal(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(eco, v.ID+".json"), data, 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	return dir
+}
+
+func TestSource(t *testing.T) {
+	d := newTestData()
+	depsDevServer := httptest.NewServer(http.HandlerFunc(d.serveDepsDev))
+	t.Cleanup(depsDevServer.Close)
+	osvServer := httptest.NewServer(http.HandlerFunc(d.serveOSV))
+	t.Cleanup(osvServer.Close)
+	depsDev := depsdevapi.NewClient(depsDevServer.Client(), depsDevServer.URL+"/v3alpha")
+	logger := zaptest.NewLogger(t)
+
+	sources := map[string]*Source{
+		"api":  NewSource(logger, depsDev, osvServer.Client(), osvServer.URL+"/v1", DefaultLookback).(*Source),
+		"dump": NewDumpSource(logger, depsDev, d.writeDump(t), DefaultLookback).(*Source),
+	}
+	for name, src := range sources {
+		t.Run(name, func(t *testing.T) {
+			u, _ := url.Parse("https://github.com/owner/repo")
+			r := &testRepo{u: u}
+			if !src.IsSupported(r) {
+				t.Fatal("IsSupported() = false, want true")
+			}
+			set, err := src.Get(context.Background(), r, "")
+			if err != nil {
+				t.Fatalf("Get() = %v, want no error", err)
+			}
+			s := set.(*vulnsSet)
+			if got, want := s.RecentCount.Get(), 2; got != want {
+				t.Errorf("RecentCount = %d, want %d", got, want)
+			}
+			if got, want := s.UnfixedCount.Get(), 1; got != want {
+				t.Errorf("UnfixedCount = %d, want %d", got, want)
+			}
+			if got, want := s.MedianDaysToFix.Get(), 20.0; got != want {
+				t.Errorf("MedianDaysToFix = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestSource_NoPackages(t *testing.T) {
+	d := newTestData()
+	depsDevServer := httptest.NewServer(http.HandlerFunc(d.serveDepsDev))
+	t.Cleanup(depsDevServer.Close)
+	depsDev := depsdevapi.NewClient(depsDevServer.Client(), depsDevServer.URL+"/v3alpha")
+	src := NewDumpSource(zaptest.NewLogger(t), depsDev, t.TempDir(), DefaultLookback)
+
+	u, _ := url.Parse("https://github.com/owner/missing")
+	set, err := src.Get(context.Background(), &testRepo{u: u}, "")
+	if err != nil {
+		t.Fatalf("Get() = %v, want no error", err)
+	}
+	if s := set.(*vulnsSet); s.RecentCount.IsSet() {
+		t.Errorf("RecentCount is set, want unset")
+	}
+}
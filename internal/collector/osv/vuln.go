@@ -0,0 +1,119 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package osv
+
+import (
+	"strings"
+	"time"
+)
+
+// Vuln contains the fields of an OSV vulnerability entry that are used for
+// collecting signals.
+//
+// See https://ossf.github.io/osv-schema/ for the complete schema.
+type Vuln struct {
+	ID        string     `json:"id"`
+	Aliases   []string   `json:"aliases"`
+	Published time.Time  `json:"published"`
+	Withdrawn *time.Time `json:"withdrawn"`
+	Affected  []Affected `json:"affected"`
+}
+
+// Affected describes the versions of a package affected by a vulnerability.
+type Affected struct {
+	Package Package `json:"package"`
+	Ranges  []Range `json:"ranges"`
+}
+
+// Package identifies a package in an ecosystem.
+type Package struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+}
+
+// Range is a range of affected versions, described by a sequence of events.
+type Range struct {
+	Type   string  `json:"type"`
+	Events []Event `json:"events"`
+}
+
+// Event marks the version at which a range starts or ends.
+//
+// Only one of the fields is set.
+type Event struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+	Limit        string `json:"limit,omitempty"`
+}
+
+// fixedVersions returns the versions of pkg that fix the vulnerability.
+//
+// Only ECOSYSTEM and SEMVER ranges are considered, as GIT ranges refer to
+// commits rather than package versions.
+func (v *Vuln) fixedVersions(pkg Package) []string {
+	var fixed []string
+	for _, a := range v.Affected {
+		if !a.Package.matches(pkg) {
+			continue
+		}
+		for _, r := range a.Ranges {
+			if r.Type == "GIT" {
+				continue
+			}
+			for _, e := range r.Events {
+				if e.Fixed != "" {
+					fixed = append(fixed, e.Fixed)
+				}
+			}
+		}
+	}
+	return fixed
+}
+
+// isUnfixed returns true if any affected range of pkg has not been fixed.
+//
+// Ranges ending in a last_affected event are treated as fixed, as they
+// indicate that later versions are not affected.
+func (v *Vuln) isUnfixed(pkg Package) bool {
+	for _, a := range v.Affected {
+		if !a.Package.matches(pkg) {
+			continue
+		}
+		for _, r := range a.Ranges {
+			if r.Type == "GIT" || len(r.Events) == 0 {
+				continue
+			}
+			last := r.Events[len(r.Events)-1]
+			if last.Fixed == "" && last.LastAffected == "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matches returns true if p and other refer to the same package.
+//
+// Names are compared case-insensitively, as some ecosystems, such as PyPI,
+// treat them this way.
+func (p Package) matches(other Package) bool {
+	return p.Ecosystem == other.Ecosystem && strings.EqualFold(p.Name, other.Name)
+}
+
+// key returns a string that uniquely identifies the package.
+func (p Package) key() string {
+	return p.Ecosystem + "/" + strings.ToLower(p.Name)
+}
@@ -24,6 +24,16 @@ type Repo interface {
 	URL() *url.URL
 }
 
+// Fork is implemented by a Repo that knows which repository it was forked
+// from.
+type Fork interface {
+	Repo
+
+	// ParentURL returns the URL of the repository this repository was forked
+	// from, or nil if it is not a fork.
+	ParentURL() *url.URL
+}
+
 // Factory is used to obtain new instances of Repo.
 type Factory interface {
 	// New returns a new instance of Repo for the supplied URL.
@@ -16,7 +16,11 @@ package collector
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/ossf/criticality_score/v2/internal/collector/projectrepo"
 	"github.com/ossf/criticality_score/v2/internal/collector/signal"
@@ -25,13 +29,38 @@ import (
 // empty is a convenience wrapper for the empty struct.
 type empty struct{}
 
+// ErrSourceTimeout is the error recorded for a Source that did not finish
+// before the SourceTimeout or RepoTimeout passed.
+var ErrSourceTimeout = errors.New("timed out")
+
+// ErrDependencyFailed is the error recorded for a Source that was not run
+// because a Source it depends on failed.
+var ErrDependencyFailed = errors.New("dependency failed")
+
 type registry struct {
 	ss []signal.Source
+
+	// concurrency limits the number of Sources run at the same time for a
+	// repository.
+	concurrency int
+
+	// failFast stops collection at the first Source that fails.
+	failFast bool
+
+	// sourceTimeout and repoTimeout limit the time spent collecting from each
+	// Source, and from all the Sources, for a repository. Zero means no limit.
+	sourceTimeout time.Duration
+	repoTimeout   time.Duration
 }
 
-// newRegistry creates a new instance of registry.
-func newRegistry() *registry {
-	return &registry{}
+// newRegistry creates a new instance of registry using the settings in c.
+func newRegistry(c *config) *registry {
+	return &registry{
+		concurrency:   c.sourceConcurrency,
+		failFast:      c.failFast,
+		sourceTimeout: c.sourceTimeout,
+		repoTimeout:   c.repoTimeout,
+	}
 }
 
 // containsSource returns true if c has already been registered.
@@ -107,19 +136,182 @@ func (r *registry) EmptySets() []signal.Set {
 //
 // An optinal jobID can be specified which is used by some sources for managing
 // caches.
+//
+// Up to r.concurrency Sources are run at the same time. A Source that
+// implements signal.Dependent is only run once the Sources it depends on have
+// finished. The Sets are returned in the order the Sources were registered.
+//
+// If a Source fails, its empty Set is used in place of its signals and
+// collection continues with the remaining Sources. The failures are returned
+// as CollectionErrors along with the Sets. Sources that depend on a failed
+// Source are not run, and fail with ErrDependencyFailed. If r.failFast is
+// true, the error from the first Source that fails is returned instead.
+//
+// Each Source is passed a context that is canceled once r.sourceTimeout has
+// passed since it started, or r.repoTimeout has passed since Collect was
+// called. A Source that fails because of this fails with ErrSourceTimeout,
+// which does not stop collection even if r.failFast is true.
 func (r *registry) Collect(ctx context.Context, repo projectrepo.Repo, jobID string) ([]signal.Set, error) {
 	cs := r.sourcesForRepository(repo)
-	var ss []signal.Set
-	for _, c := range cs {
-		s, err := c.Get(ctx, repo, jobID)
-		if err != nil {
-			return nil, err
+	deps, err := dependencies(cs)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.repoTimeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, r.repoTimeout)
+		defer cancelTimeout()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	sem := make(chan empty, max(r.concurrency, 1))
+	ss := make([]signal.Set, len(cs))
+	errs := make([]error, len(cs))
+	done := make([]chan empty, len(cs))
+	for i := range cs {
+		done[i] = make(chan empty)
+	}
+	for i, c := range cs {
+		wg.Add(1)
+		go func(i int, c signal.Source) {
+			defer wg.Done()
+			defer close(done[i])
+
+			// Wait for the dependencies before taking a slot, so a slot is
+			// never held by a Source that can not run yet.
+			var depSets []signal.Set
+			for _, d := range deps[i] {
+				<-done[d]
+				if errs[d] != nil {
+					errs[i] = fmt.Errorf("%w: %s", ErrDependencyFailed, cs[d].EmptySet().Namespace())
+					return
+				}
+				depSets = append(depSets, ss[d])
+			}
+			select {
+			case sem <- empty{}:
+			case <-ctx.Done():
+				errs[i] = timeoutError(ctx, ctx.Err())
+				return
+			}
+			s, err := r.get(ctx, c, depSets, repo, jobID)
+			<-sem
+			if err != nil {
+				errs[i] = err
+				if r.failFast && !errors.Is(err, ErrSourceTimeout) {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+				}
+				return
+			}
+			ss[i] = s
+		}(i, c)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	var collectErrs CollectionErrors
+	for i, c := range cs {
+		if errs[i] != nil {
+			collectErrs = append(collectErrs, &SourceError{Namespace: c.EmptySet().Namespace(), Err: errs[i]})
+			ss[i] = c.EmptySet()
 		}
-		ss = append(ss, s)
+	}
+	if len(collectErrs) > 0 {
+		return ss, collectErrs
 	}
 	return ss, nil
 }
 
+// get returns the Set from the Source c, limiting it to r.sourceTimeout.
+func (r *registry) get(ctx context.Context, c signal.Source, deps []signal.Set, repo projectrepo.Repo, jobID string) (signal.Set, error) {
+	if r.sourceTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.sourceTimeout)
+		defer cancel()
+	}
+	s, err := c.Get(signal.WithDependencies(ctx, deps), repo, jobID)
+	if err != nil {
+		return nil, timeoutError(ctx, err)
+	}
+	return s, nil
+}
+
+// timeoutError wraps err with ErrSourceTimeout if it was caused by the
+// deadline for ctx passing.
+func timeoutError(ctx context.Context, err error) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %w", ErrSourceTimeout, err)
+	}
+	return err
+}
+
+// dependencies returns the indices of the Sources each Source in cs depends
+// on.
+//
+// An error is returned if the dependencies contain a cycle, as the Sources
+// could never be run.
+func dependencies(cs []signal.Source) ([][]int, error) {
+	index := make(map[signal.Namespace]int)
+	for i, c := range cs {
+		index[c.EmptySet().Namespace()] = i
+	}
+	deps := make([][]int, len(cs))
+	for i, c := range cs {
+		d, ok := c.(signal.Dependent)
+		if !ok {
+			continue
+		}
+		for _, ns := range d.DependsOn() {
+			if j, ok := index[ns]; ok {
+				deps[i] = append(deps[i], j)
+			}
+		}
+	}
+
+	// Check for cycles by repeatedly marking the Sources whose dependencies
+	// are all marked. Any Source left unmarked is part of a cycle.
+	ready := make([]bool, len(cs))
+	for n := 0; n < len(cs); {
+		progress := false
+		for i := range cs {
+			if ready[i] {
+				continue
+			}
+			ok := true
+			for _, j := range deps[i] {
+				ok = ok && ready[j]
+			}
+			if ok {
+				ready[i] = true
+				progress = true
+				n++
+			}
+		}
+		if !progress {
+			var cycle []string
+			for i, c := range cs {
+				if !ready[i] {
+					cycle = append(cycle, c.EmptySet().Namespace().String())
+				}
+			}
+			return nil, fmt.Errorf("dependency cycle between sources: %s", strings.Join(cycle, ", "))
+		}
+	}
+	return deps, nil
+}
+
 func validateSource(s signal.Source) {
 	// TODO - ensure a source with the same Namespace as another use
 	// the same signal.Set
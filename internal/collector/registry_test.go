@@ -0,0 +1,328 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ossf/criticality_score/v2/internal/collector/projectrepo"
+	"github.com/ossf/criticality_score/v2/internal/collector/signal"
+)
+
+type testRepo struct{}
+
+func (r *testRepo) URL() *url.URL {
+	return &url.URL{Scheme: "https", Host: "example.com", Path: "/owner/repo"}
+}
+
+type testSet struct {
+	Count signal.Field[int]
+	ns    signal.Namespace
+}
+
+func (s *testSet) Namespace() signal.Namespace {
+	return s.ns
+}
+
+// testSource returns a Set with Count set to 1, or err if it is not nil.
+type testSource struct {
+	ns  signal.Namespace
+	err error
+}
+
+func (s *testSource) EmptySet() signal.Set {
+	return &testSet{ns: s.ns}
+}
+
+func (s *testSource) IsSupported(projectrepo.Repo) bool {
+	return true
+}
+
+func (s *testSource) Get(context.Context, projectrepo.Repo, string) (signal.Set, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &testSet{ns: s.ns, Count: signal.Val(1)}, nil
+}
+
+func newTestRegistry(errMentions error) *registry {
+	r := newRegistry(&config{sourceConcurrency: DefaultSourceConcurrency})
+	r.Register(&testSource{ns: "repo"})
+	r.Register(&testSource{ns: "mentions", err: errMentions})
+	r.Register(&testSource{ns: "issues"})
+	return r
+}
+
+func TestRegistryCollect(t *testing.T) {
+	r := newTestRegistry(nil)
+	ss, err := r.Collect(context.Background(), &testRepo{}, "")
+	if err != nil {
+		t.Fatalf("Collect() = %v, want no error", err)
+	}
+	if len(ss) != 3 {
+		t.Fatalf("Collect() returned %d sets, want 3", len(ss))
+	}
+}
+
+func TestRegistryCollect_PartialResults(t *testing.T) {
+	errSearch := errors.New("search failed")
+	r := newTestRegistry(errSearch)
+	ss, err := r.Collect(context.Background(), &testRepo{}, "")
+
+	var errs CollectionErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Collect() = %v, want CollectionErrors", err)
+	}
+	if len(errs) != 1 || errs[0].Namespace != "mentions" || !errors.Is(errs[0], errSearch) {
+		t.Fatalf("Collect() = %v, want a single error for mentions", errs)
+	}
+	if got, want := err.Error(), "mentions: search failed"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+
+	if len(ss) != 3 {
+		t.Fatalf("Collect() returned %d sets, want 3", len(ss))
+	}
+	for _, s := range ss {
+		ts := s.(*testSet)
+		if got, want := ts.Count.IsSet(), ts.ns != "mentions"; got != want {
+			t.Errorf("%s Count.IsSet() = %v, want %v", ts.ns, got, want)
+		}
+	}
+}
+
+func TestRegistryCollect_FailFast(t *testing.T) {
+	errSearch := errors.New("search failed")
+	r := newTestRegistry(errSearch)
+	r.failFast = true
+	ss, err := r.Collect(context.Background(), &testRepo{}, "")
+	if !errors.Is(err, errSearch) {
+		t.Fatalf("Collect() = %v, want %v", err, errSearch)
+	}
+	if ss != nil {
+		t.Fatalf("Collect() returned %d sets, want none", len(ss))
+	}
+}
+
+// blockingSource waits until all the Sources sharing started have started
+// before returning its Set, so it can only succeed if they run concurrently.
+type blockingSource struct {
+	testSource
+	started *sync.WaitGroup
+}
+
+func (s *blockingSource) Get(ctx context.Context, r projectrepo.Repo, jobID string) (signal.Set, error) {
+	s.started.Done()
+	done := make(chan struct{})
+	go func() {
+		s.started.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		return nil, fmt.Errorf("%s: timed out waiting for other sources", s.ns)
+	}
+	return s.testSource.Get(ctx, r, jobID)
+}
+
+func TestRegistryCollect_Concurrent(t *testing.T) {
+	names := []signal.Namespace{"a", "b", "c", "d"}
+	r := newRegistry(&config{sourceConcurrency: len(names)})
+	var started sync.WaitGroup
+	started.Add(len(names))
+	for _, ns := range names {
+		r.Register(&blockingSource{testSource: testSource{ns: ns}, started: &started})
+	}
+	ss, err := r.Collect(context.Background(), &testRepo{}, "")
+	if err != nil {
+		t.Fatalf("Collect() = %v, want no error", err)
+	}
+	if len(ss) != len(names) {
+		t.Fatalf("Collect() returned %d sets, want %d", len(ss), len(names))
+	}
+	for i, s := range ss {
+		if got, want := s.Namespace(), names[i]; got != want {
+			t.Errorf("Collect()[%d].Namespace() = %q, want %q", i, got, want)
+		}
+	}
+}
+
+// countingSource records the largest number of Sources sharing running that
+// were running at once.
+type countingSource struct {
+	testSource
+	running, peak *atomic.Int32
+}
+
+func (s *countingSource) Get(ctx context.Context, r projectrepo.Repo, jobID string) (signal.Set, error) {
+	n := s.running.Add(1)
+	defer s.running.Add(-1)
+	for {
+		p := s.peak.Load()
+		if n <= p || s.peak.CompareAndSwap(p, n) {
+			break
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	return s.testSource.Get(ctx, r, jobID)
+}
+
+func TestRegistryCollect_ConcurrencyLimit(t *testing.T) {
+	const limit = 2
+	r := newRegistry(&config{sourceConcurrency: limit})
+	var running, peak atomic.Int32
+	for i := 0; i < 6; i++ {
+		r.Register(&countingSource{
+			testSource: testSource{ns: signal.Namespace(fmt.Sprintf("s%d", i))},
+			running:    &running,
+			peak:       &peak,
+		})
+	}
+	if _, err := r.Collect(context.Background(), &testRepo{}, ""); err != nil {
+		t.Fatalf("Collect() = %v, want no error", err)
+	}
+	if got := peak.Load(); got > limit {
+		t.Fatalf("%d sources ran at once, want at most %d", got, limit)
+	}
+}
+
+// derivedSource depends on the Sets for deps, and sets Count to the sum of
+// their Counts.
+type derivedSource struct {
+	testSource
+	deps []signal.Namespace
+}
+
+func (s *derivedSource) DependsOn() []signal.Namespace {
+	return s.deps
+}
+
+func (s *derivedSource) Get(ctx context.Context, _ projectrepo.Repo, _ string) (signal.Set, error) {
+	total := 0
+	for _, ns := range s.deps {
+		d, ok := signal.Dependency(ctx, ns)
+		if !ok {
+			continue
+		}
+		total += d.(*testSet).Count.Get()
+	}
+	return &testSet{ns: s.ns, Count: signal.Val(total)}, nil
+}
+
+func TestRegistryCollect_Dependencies(t *testing.T) {
+	r := newRegistry(&config{sourceConcurrency: DefaultSourceConcurrency})
+	// Register the derived Sources first to check they still wait.
+	r.Register(&derivedSource{testSource: testSource{ns: "total"}, deps: []signal.Namespace{"repo", "sum", "missing"}})
+	r.Register(&derivedSource{testSource: testSource{ns: "sum"}, deps: []signal.Namespace{"repo", "issues"}})
+	r.Register(&testSource{ns: "repo"})
+	r.Register(&testSource{ns: "issues"})
+
+	ss, err := r.Collect(context.Background(), &testRepo{}, "")
+	if err != nil {
+		t.Fatalf("Collect() = %v, want no error", err)
+	}
+	want := map[signal.Namespace]int{"total": 3, "sum": 2, "repo": 1, "issues": 1}
+	for _, s := range ss {
+		ts := s.(*testSet)
+		if got := ts.Count.Get(); got != want[ts.ns] {
+			t.Errorf("%s Count = %d, want %d", ts.ns, got, want[ts.ns])
+		}
+	}
+}
+
+func TestRegistryCollect_DependencyFailed(t *testing.T) {
+	errSearch := errors.New("search failed")
+	r := newTestRegistry(errSearch)
+	r.Register(&derivedSource{testSource: testSource{ns: "derived"}, deps: []signal.Namespace{"mentions"}})
+
+	ss, err := r.Collect(context.Background(), &testRepo{}, "")
+	var errs CollectionErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Collect() = %v, want CollectionErrors", err)
+	}
+	if len(errs) != 2 || errs[1].Namespace != "derived" || !errors.Is(errs[1], ErrDependencyFailed) {
+		t.Fatalf("Collect() = %v, want errors for mentions and derived", errs)
+	}
+	if len(ss) != 4 {
+		t.Fatalf("Collect() returned %d sets, want 4", len(ss))
+	}
+	if ts := ss[3].(*testSet); ts.Count.IsSet() {
+		t.Errorf("derived Count is set, want unset")
+	}
+}
+
+func TestRegistryCollect_DependencyCycle(t *testing.T) {
+	r := newRegistry(&config{sourceConcurrency: DefaultSourceConcurrency})
+	r.Register(&testSource{ns: "repo"})
+	r.Register(&derivedSource{testSource: testSource{ns: "a"}, deps: []signal.Namespace{"b"}})
+	r.Register(&derivedSource{testSource: testSource{ns: "b"}, deps: []signal.Namespace{"a"}})
+
+	if _, err := r.Collect(context.Background(), &testRepo{}, ""); err == nil {
+		t.Fatal("Collect() = nil, want an error")
+	}
+}
+
+// slowSource blocks until its context is done.
+type slowSource struct {
+	testSource
+}
+
+func (s *slowSource) Get(ctx context.Context, _ projectrepo.Repo, _ string) (signal.Set, error) {
+	<-ctx.Done()
+	return nil, fmt.Errorf("waiting: %w", ctx.Err())
+}
+
+func TestRegistryCollect_Timeouts(t *testing.T) {
+	tests := []struct {
+		c    *config
+		name string
+	}{
+		{name: "source", c: &config{sourceConcurrency: 2, sourceTimeout: 10 * time.Millisecond, failFast: true}},
+		{name: "repo", c: &config{sourceConcurrency: 2, repoTimeout: 10 * time.Millisecond, failFast: true}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := newRegistry(test.c)
+			r.Register(&testSource{ns: "repo"})
+			r.Register(&slowSource{testSource{ns: "slow"}})
+
+			ss, err := r.Collect(context.Background(), &testRepo{}, "")
+			var errs CollectionErrors
+			if !errors.As(err, &errs) {
+				t.Fatalf("Collect() = %v, want CollectionErrors", err)
+			}
+			if len(errs) != 1 || errs[0].Namespace != "slow" || !errors.Is(errs[0], ErrSourceTimeout) {
+				t.Fatalf("Collect() = %v, want a timeout for slow", errs)
+			}
+			if len(ss) != 2 {
+				t.Fatalf("Collect() returned %d sets, want 2", len(ss))
+			}
+			if ts := ss[0].(*testSet); !ts.Count.IsSet() {
+				t.Errorf("repo Count is unset, want set")
+			}
+			if ts := ss[1].(*testSet); ts.Count.IsSet() {
+				t.Errorf("slow Count is set, want unset")
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorecard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"gocloud.dev/blob"
+	"gocloud.dev/gcerrors"
+
+	"github.com/ossf/criticality_score/v2/internal/retry"
+)
+
+// DefaultAPIURL is the base URL of the public Scorecard API.
+const DefaultAPIURL = "https://api.securityscorecards.dev"
+
+// result contains the fields of a Scorecard JSON result that are used for
+// collecting signals.
+type result struct {
+	Date   string  `json:"date"`
+	Score  float64 `json:"score"`
+	Checks []struct {
+		Name  string `json:"name"`
+		Score int    `json:"score"`
+	} `json:"checks"`
+}
+
+// date returns the date the result was produced.
+//
+// Results from the Scorecard CLI only include the date, while results from
+// the API include the time as well.
+func (r *result) date() (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, r.Date); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// resultReader is used to find the Scorecard result for a repository.
+type resultReader interface {
+	// Result returns the result for the repository with the given name, such
+	// as "github.com/ossf/scorecard". If there is no result, nil is returned.
+	Result(ctx context.Context, name string) (*result, error)
+}
+
+// bucketReader is a resultReader backed by a bucket or local directory of
+// Scorecard JSON results.
+//
+// The result for each repository is stored in a file named after the
+// repository in lowercase, such as "github.com/ossf/scorecard.json".
+type bucketReader struct {
+	bucket *blob.Bucket
+}
+
+// Result implements the resultReader interface.
+func (r *bucketReader) Result(ctx context.Context, name string) (*result, error) {
+	data, err := r.bucket.ReadAll(ctx, name+".json")
+	if gcerrors.Code(err) == gcerrors.NotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read result: %w", err)
+	}
+	res := &result{}
+	if err := json.Unmarshal(data, res); err != nil {
+		return nil, fmt.Errorf("parse result for %s: %w", name, err)
+	}
+	return res, nil
+}
+
+// apiClient is a resultReader backed by the Scorecard API.
+type apiClient struct {
+	http    *http.Client
+	baseURL string
+}
+
+// errNotFound is returned when the Scorecard API responds with a 404.
+var errNotFound = errors.New("not found")
+
+// Result implements the resultReader interface.
+func (c *apiClient) Result(ctx context.Context, name string) (*result, error) {
+	res := &result{}
+	err := c.get(ctx, "projects/"+name, res)
+	if errors.Is(err, errNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// get requests the API endpoint at path and parses the JSON response into v.
+func (c *apiClient) get(ctx context.Context, path string, v any) error {
+	u := strings.TrimSuffix(c.baseURL, "/") + "/" + path
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return fmt.Errorf("new request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("scorecard request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("%w: %s", errNotFound, u)
+	case resp.StatusCode < 200 || 300 <= resp.StatusCode:
+		return fmt.Errorf("scorecard request %s: unexpected status %s", u, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("reading response for %s: %w", u, err)
+	}
+	return nil
+}
+
+// NewTransport returns an http.RoundTripper for communicating with the
+// Scorecard API.
+//
+// Requests are retried if they are rate limited or fail with a server error.
+func NewTransport(inner http.RoundTripper) http.RoundTripper {
+	return retry.NewRoundTripper(inner,
+		retry.InitialDelay(time.Minute),
+		retry.RetryAfter(retry.RetryAfterSeconds),
+		retry.Strategy(retry.TooManyRequests),
+		retry.Strategy(retry.ServerError),
+	)
+}
@@ -0,0 +1,166 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package scorecard provides a signal Source for the results of the OpenSSF
+// Scorecard checks for a repository.
+//
+// Results are read from the Scorecard API, or from a bucket or local
+// directory of Scorecard JSON results.
+package scorecard
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ossf/criticality_score/v2/internal/cloudstorage"
+	"github.com/ossf/criticality_score/v2/internal/collector/projectrepo"
+	"github.com/ossf/criticality_score/v2/internal/collector/signal"
+)
+
+// supportedHosts is the set of hostnames that Scorecard produces results for.
+var supportedHosts = map[string]bool{
+	"github.com": true,
+	"gitlab.com": true,
+}
+
+//nolint:govet
+type scorecardSet struct {
+	Score signal.Field[float64]   `desc:"Aggregate OpenSSF Scorecard score, from 0 to 10." unit:"score" source:"OpenSSF Scorecard"`
+	Date  signal.Field[time.Time] `desc:"When the Scorecard result was produced." source:"OpenSSF Scorecard"`
+
+	BinaryArtifacts      signal.Field[int] `desc:"Score of the Binary-Artifacts check, from 0 to 10." unit:"score" source:"OpenSSF Scorecard"`
+	BranchProtection     signal.Field[int] `desc:"Score of the Branch-Protection check, from 0 to 10." unit:"score" source:"OpenSSF Scorecard"`
+	CIIBestPractices     signal.Field[int] `desc:"Score of the CII-Best-Practices check, from 0 to 10." unit:"score" source:"OpenSSF Scorecard"`
+	CITests              signal.Field[int] `desc:"Score of the CI-Tests check, from 0 to 10." unit:"score" source:"OpenSSF Scorecard"`
+	CodeReview           signal.Field[int] `desc:"Score of the Code-Review check, from 0 to 10." unit:"score" source:"OpenSSF Scorecard"`
+	Contributors         signal.Field[int] `desc:"Score of the Contributors check, from 0 to 10." unit:"score" source:"OpenSSF Scorecard"`
+	DangerousWorkflow    signal.Field[int] `desc:"Score of the Dangerous-Workflow check, from 0 to 10." unit:"score" source:"OpenSSF Scorecard"`
+	DependencyUpdateTool signal.Field[int] `desc:"Score of the Dependency-Update-Tool check, from 0 to 10." unit:"score" source:"OpenSSF Scorecard"`
+	Fuzzing              signal.Field[int] `desc:"Score of the Fuzzing check, from 0 to 10." unit:"score" source:"OpenSSF Scorecard"`
+	License              signal.Field[int] `desc:"Score of the License check, from 0 to 10." unit:"score" source:"OpenSSF Scorecard"`
+	Maintained           signal.Field[int] `desc:"Score of the Maintained check, from 0 to 10." unit:"score" source:"OpenSSF Scorecard"`
+	Packaging            signal.Field[int] `desc:"Score of the Packaging check, from 0 to 10." unit:"score" source:"OpenSSF Scorecard"`
+	PinnedDependencies   signal.Field[int] `desc:"Score of the Pinned-Dependencies check, from 0 to 10." unit:"score" source:"OpenSSF Scorecard"`
+	SAST                 signal.Field[int] `desc:"Score of the SAST check, from 0 to 10." unit:"score" source:"OpenSSF Scorecard"`
+	SecurityPolicy       signal.Field[int] `desc:"Score of the Security-Policy check, from 0 to 10." unit:"score" source:"OpenSSF Scorecard"`
+	SignedReleases       signal.Field[int] `desc:"Score of the Signed-Releases check, from 0 to 10." unit:"score" source:"OpenSSF Scorecard"`
+	TokenPermissions     signal.Field[int] `desc:"Score of the Token-Permissions check, from 0 to 10." unit:"score" source:"OpenSSF Scorecard"`
+	Vulnerabilities      signal.Field[int] `desc:"Score of the Vulnerabilities check, from 0 to 10." unit:"score" source:"OpenSSF Scorecard"`
+	Webhooks             signal.Field[int] `desc:"Score of the Webhooks check, from 0 to 10." unit:"score" source:"OpenSSF Scorecard"`
+}
+
+func (s *scorecardSet) Namespace() signal.Namespace {
+	return signal.Namespace("scorecard")
+}
+
+// checkFields returns the field in s for each Scorecard check, keyed by the
+// name of the check.
+func (s *scorecardSet) checkFields() map[string]*signal.Field[int] {
+	return map[string]*signal.Field[int]{
+		"Binary-Artifacts":       &s.BinaryArtifacts,
+		"Branch-Protection":      &s.BranchProtection,
+		"CII-Best-Practices":     &s.CIIBestPractices,
+		"CI-Tests":               &s.CITests,
+		"Code-Review":            &s.CodeReview,
+		"Contributors":           &s.Contributors,
+		"Dangerous-Workflow":     &s.DangerousWorkflow,
+		"Dependency-Update-Tool": &s.DependencyUpdateTool,
+		"Fuzzing":                &s.Fuzzing,
+		"License":                &s.License,
+		"Maintained":             &s.Maintained,
+		"Packaging":              &s.Packaging,
+		"Pinned-Dependencies":    &s.PinnedDependencies,
+		"SAST":                   &s.SAST,
+		"Security-Policy":        &s.SecurityPolicy,
+		"Signed-Releases":        &s.SignedReleases,
+		"Token-Permissions":      &s.TokenPermissions,
+		"Vulnerabilities":        &s.Vulnerabilities,
+		"Webhooks":               &s.Webhooks,
+	}
+}
+
+type Source struct {
+	logger  *zap.Logger
+	results resultReader
+}
+
+// NewSource creates a new Source that reads results from the Scorecard API at
+// apiURL.
+func NewSource(logger *zap.Logger, client *http.Client, apiURL string) signal.Source {
+	return &Source{
+		logger:  logger,
+		results: &apiClient{http: client, baseURL: apiURL},
+	}
+}
+
+// NewBucketSource creates a new Source that reads results from the bucket or
+// local directory at rawURL.
+//
+// The result for each repository must be stored in a file named after the
+// repository in lowercase, such as "github.com/ossf/scorecard.json".
+func NewBucketSource(ctx context.Context, logger *zap.Logger, rawURL string) (signal.Source, error) {
+	b, err := cloudstorage.OpenBucket(ctx, rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("open scorecard results: %w", err)
+	}
+	return &Source{
+		logger:  logger,
+		results: &bucketReader{bucket: b},
+	}, nil
+}
+
+func (c *Source) EmptySet() signal.Set {
+	return &scorecardSet{}
+}
+
+func (c *Source) IsSupported(r projectrepo.Repo) bool {
+	return supportedHosts[strings.ToLower(r.URL().Hostname())]
+}
+
+func (c *Source) Get(ctx context.Context, r projectrepo.Repo, _ string) (signal.Set, error) {
+	s := &scorecardSet{}
+	name := repoName(r)
+	c.logger.With(zap.String("url", r.URL().String())).Debug("Fetching scorecard result")
+	res, err := c.results.Result(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("fetch scorecard result: %w", err)
+	}
+	if res == nil {
+		return s, nil
+	}
+	s.Score.Set(res.Score)
+	if t, ok := res.date(); ok {
+		s.Date.Set(t)
+	}
+	fields := s.checkFields()
+	for _, check := range res.Checks {
+		// A score of -1 indicates the check was inconclusive.
+		if f, ok := fields[check.Name]; ok && check.Score >= 0 {
+			f.Set(check.Score)
+		}
+	}
+	return s, nil
+}
+
+// repoName returns the name Scorecard uses for the repository, such as
+// "github.com/ossf/scorecard".
+func repoName(r projectrepo.Repo) string {
+	u := r.URL()
+	return strings.ToLower(u.Hostname() + "/" + strings.Trim(strings.TrimSuffix(u.Path, ".git"), "/"))
+}
@@ -0,0 +1,142 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorecard
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+
+	"github.com/ossf/criticality_score/v2/internal/collector/signal"
+)
+
+// testResult is a trimmed down Scorecard JSON result.
+const testResult = `{
+  "date": "2023-06-12T05:04:37Z",
+  "repo": {"name": "github.com/owner/repo", "commit": "abc123"},
+  "scorecard": {"version": "v4.10.5", "commit": "def456"},
+  "score": 6.4,
+  "checks": [
+    {"name": "Binary-Artifacts", "score": 10, "reason": "no binaries found in the repo"},
+    {"name": "CI-Tests", "score": 8, "reason": "8 out of 10 merged PRs checked by a CI test"},
+    {"name": "Packaging", "score": -1, "reason": "no published package detected"},
+    {"name": "Unknown-Check", "score": 5, "reason": "a check added in a later release"}
+  ]
+}`
+
+type testRepo struct {
+	u *url.URL
+}
+
+func (r *testRepo) URL() *url.URL {
+	return r.u
+}
+
+func TestSource(t *testing.T) {
+	ctx := context.Background()
+	logger := zaptest.NewLogger(t)
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/projects/github.com/owner/repo" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(testResult))
+	}))
+	t.Cleanup(s.Close)
+
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "github.com", "owner"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "github.com", "owner", "repo.json"), []byte(testResult), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	bucketSource, err := NewBucketSource(ctx, logger, dir)
+	if err != nil {
+		t.Fatalf("NewBucketSource() = %v, want no error", err)
+	}
+
+	sources := map[string]signal.Source{
+		"api":    NewSource(logger, s.Client(), s.URL),
+		"bucket": bucketSource,
+	}
+	for name, src := range sources {
+		t.Run(name, func(t *testing.T) {
+			u, _ := url.Parse("https://github.com/Owner/Repo.git")
+			set, err := src.Get(ctx, &testRepo{u: u}, "")
+			if err != nil {
+				t.Fatalf("Get() = %v, want no error", err)
+			}
+			s := set.(*scorecardSet)
+			if got, want := s.Score.Get(), 6.4; got != want {
+				t.Errorf("Score = %v, want %v", got, want)
+			}
+			if got, want := s.Date.Get(), time.Date(2023, 6, 12, 5, 4, 37, 0, time.UTC); !got.Equal(want) {
+				t.Errorf("Date = %v, want %v", got, want)
+			}
+			if got, want := s.BinaryArtifacts.Get(), 10; got != want {
+				t.Errorf("BinaryArtifacts = %d, want %d", got, want)
+			}
+			if got, want := s.CITests.Get(), 8; got != want {
+				t.Errorf("CITests = %d, want %d", got, want)
+			}
+			if s.Packaging.IsSet() {
+				t.Errorf("Packaging is set, want unset")
+			}
+			if s.Fuzzing.IsSet() {
+				t.Errorf("Fuzzing is set, want unset")
+			}
+
+			u, _ = url.Parse("https://github.com/owner/missing")
+			set, err = src.Get(ctx, &testRepo{u: u}, "")
+			if err != nil {
+				t.Fatalf("Get() = %v, want no error", err)
+			}
+			if s := set.(*scorecardSet); s.Score.IsSet() {
+				t.Errorf("Score is set for missing result, want unset")
+			}
+		})
+	}
+}
+
+func TestSource_IsSupported(t *testing.T) {
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{url: "https://github.com/owner/repo", want: true},
+		{url: "https://GitLab.com/owner/repo", want: true},
+		{url: "https://codeberg.org/owner/repo", want: false},
+		{url: "https://gitlab.example.com/owner/repo", want: false},
+	}
+	src := NewSource(zaptest.NewLogger(t), http.DefaultClient, "")
+	for _, test := range tests {
+		t.Run(test.url, func(t *testing.T) {
+			u, _ := url.Parse(test.url)
+			if got := src.IsSupported(&testRepo{u: u}); got != test.want {
+				t.Errorf("IsSupported() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signal
+
+import "reflect"
+
+// detailerType caches the reflect.Type representation of the detailer
+// interface.
+var detailerType = reflect.TypeOf((*detailer)(nil)).Elem()
+
+// detailer is a marker interface used to identify Detail fields in a struct.
+type detailer interface {
+	valuer
+	detail()
+}
+
+// Detail is similar to Field, but holds a value that is not a scalar, such as
+// a slice or a map.
+//
+// As a Detail can not be represented in a single column, it is only included
+// in output that preserves the structure of each Set, such as JSON. SetFields,
+// SetValues and SetAsMap skip all Detail fields.
+type Detail[T any] struct {
+	value T
+	set   bool
+}
+
+func (d *Detail[T]) Set(v T) {
+	d.value = v
+	d.set = true
+}
+
+func (d *Detail[T]) Get() T {
+	if !d.set {
+		var zero T
+		return zero
+	}
+	return d.value
+}
+
+func (d *Detail[T]) IsSet() bool {
+	return d.set
+}
+
+func (d *Detail[T]) Unset() {
+	d.set = false
+}
+
+func (d Detail[T]) Value() any {
+	if !d.set {
+		return nil
+	} else {
+		return d.value
+	}
+}
+
+func (d Detail[T]) detail() {}
@@ -15,9 +15,25 @@
 package signal
 
 type IssuesSet struct {
-	UpdatedCount     Field[int]     `signal:"updated_issues_count,legacy"`
-	ClosedCount      Field[int]     `signal:"closed_issues_count,legacy"`
-	CommentFrequency Field[float64] `signal:"issue_comment_frequency,legacy"`
+	UpdatedCount     Field[int]     `signal:"updated_issues_count,legacy" desc:"Number of issues updated." unit:"count" lookback:"90 days" source:"GitHub, GitLab or Gitea"`
+	ClosedCount      Field[int]     `signal:"closed_issues_count,legacy" desc:"Number of issues closed." unit:"count" lookback:"90 days" source:"GitHub, GitLab or Gitea"`
+	CommentFrequency Field[float64] `signal:"issue_comment_frequency,legacy" desc:"Average number of comments per updated issue." unit:"comments/issue" lookback:"90 days" source:"GitHub, GitLab or Gitea"`
+
+	// OpenCount is the number of issues that are currently open.
+	OpenCount Field[int] `desc:"Number of open issues." unit:"count" source:"GitHub"`
+
+	// MedianHoursToFirstResponse is the median number of hours between an
+	// issue being opened and the first comment by an owner, member or
+	// collaborator of the repository.
+	MedianHoursToFirstResponse Field[float64] `desc:"Median time between an issue being opened and the first response by a maintainer." unit:"hours" lookback:"90 days" source:"GitHub"`
+
+	// MedianHoursToClose is the median number of hours between an issue being
+	// opened and being closed, for issues closed during the lookback period.
+	MedianHoursToClose Field[float64] `desc:"Median time between an issue being opened and being closed." unit:"hours" lookback:"90 days" source:"GitHub"`
+
+	// ClosedToOpenedRatio is the number of issues closed during the lookback
+	// period divided by the number of issues opened during it.
+	ClosedToOpenedRatio Field[float64] `desc:"Number of issues closed divided by the number of issues opened." unit:"ratio" lookback:"90 days" source:"GitHub"`
 }
 
 func (r *IssuesSet) Namespace() Namespace {
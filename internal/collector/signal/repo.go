@@ -18,22 +18,34 @@ import "time"
 
 //nolint:govet
 type RepoSet struct {
-	URL      Field[string]
-	Language Field[string]
-	License  Field[string]
-
-	StarCount Field[int]
-	CreatedAt Field[time.Time]
-	UpdatedAt Field[time.Time]
-
-	CreatedSince Field[int] `signal:"legacy"`
-	UpdatedSince Field[int] `signal:"legacy"`
-
-	ContributorCount Field[int] `signal:"legacy"`
-	OrgCount         Field[int] `signal:"legacy"`
-
-	CommitFrequency    Field[float64] `signal:"legacy"`
-	RecentReleaseCount Field[int]     `signal:"legacy"`
+	URL      Field[string] `desc:"URL of the repository." source:"GitHub, GitLab, Gitea or git"`
+	Language Field[string] `desc:"Primary programming language of the repository." source:"GitHub, GitLab or Gitea"`
+	License  Field[string] `desc:"License of the repository." source:"GitHub, GitLab or Gitea"`
+
+	StarCount Field[int]       `desc:"Number of stars given to the repository." unit:"count" source:"GitHub, GitLab or Gitea"`
+	CreatedAt Field[time.Time] `desc:"When the repository was created." source:"GitHub, GitLab, Gitea or git"`
+	UpdatedAt Field[time.Time] `desc:"When the repository was last updated." source:"GitHub, GitLab, Gitea or git"`
+
+	CreatedSince Field[int] `signal:"legacy" desc:"Number of months since the repository was created." unit:"months" source:"GitHub, GitLab, Gitea or git"`
+	UpdatedSince Field[int] `signal:"legacy" desc:"Number of months since the repository was last updated." unit:"months" source:"GitHub, GitLab, Gitea or git"`
+
+	ContributorCount Field[int] `signal:"legacy" desc:"Number of contributors to the repository, up to 5000." unit:"count" source:"GitHub, GitLab, Gitea or git"`
+	OrgCount         Field[int] `signal:"legacy" desc:"Number of distinct organizations among the top contributors." unit:"count" source:"GitHub or git"`
+
+	CommitFrequency    Field[float64] `signal:"legacy" desc:"Average number of commits per week." unit:"commits/week" lookback:"1 year" source:"GitHub, GitLab, Gitea or git"`
+	RecentReleaseCount Field[int]     `signal:"legacy" desc:"Number of releases." unit:"count" lookback:"1 year" source:"GitHub, GitLab, Gitea or git"`
+
+	WatcherCount Field[int]    `desc:"Number of users watching the repository." unit:"count" source:"GitHub or Gitea"`
+	ForkCount    Field[int]    `desc:"Number of forks of the repository." unit:"count" source:"GitHub, GitLab or Gitea"`
+	MirrorURL    Field[string] `desc:"URL of the repository being mirrored, if the repository is a mirror." source:"GitHub or Gitea"`
+	ParentURL    Field[string] `desc:"URL of the repository this repository was forked from." source:"GitHub, GitLab or Gitea"`
+
+	IsArchived       Field[bool] `desc:"Whether the repository is archived." source:"GitHub, GitLab or Gitea"`
+	IsMirror         Field[bool] `desc:"Whether the repository is a mirror of another repository." source:"GitHub or Gitea"`
+	IsDisabled       Field[bool] `desc:"Whether the repository is disabled." source:"GitHub"`
+	IsEmpty          Field[bool] `desc:"Whether the repository has no commits." source:"GitHub, GitLab or Gitea"`
+	HasIssuesEnabled Field[bool] `desc:"Whether the repository has an issue tracker enabled." source:"GitHub, GitLab or Gitea"`
+	IsFork           Field[bool] `desc:"Whether the repository is a fork of another repository." source:"GitHub, GitLab or Gitea"`
 }
 
 func (r *RepoSet) Namespace() Namespace {
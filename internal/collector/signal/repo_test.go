@@ -0,0 +1,47 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signal
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestRepoSetColumnOrder ensures new RepoSet fields are added after the
+// original ones, so the columns loaded by position from CSV output keep their
+// place.
+func TestRepoSetColumnOrder(t *testing.T) {
+	want := []string{
+		"repo.url",
+		"repo.language",
+		"repo.license",
+		"repo.star_count",
+		"repo.created_at",
+		"repo.updated_at",
+		"legacy.created_since",
+		"legacy.updated_since",
+		"legacy.contributor_count",
+		"legacy.org_count",
+		"legacy.commit_frequency",
+		"legacy.recent_release_count",
+	}
+	var got []string
+	for _, d := range Describe(&RepoSet{}) {
+		got = append(got, d.Name)
+	}
+	if len(got) < len(want) || !reflect.DeepEqual(got[:len(want)], want) {
+		t.Fatalf("Describe() names = %v, want prefix %v", got, want)
+	}
+}
@@ -44,6 +44,13 @@ const (
 	fieldTagIgnore    = "-"
 	fieldTagLegacy    = "legacy"
 	fieldTagSeperator = ","
+
+	// The following constants are the tags used for describing the struct
+	// fields in a Set.
+	fieldTagDescription = "desc"
+	fieldTagUnit        = "unit"
+	fieldTagLookback    = "lookback"
+	fieldTagSource      = "source"
 )
 
 const (
@@ -64,7 +71,7 @@ type SupportedType interface {
 	~int | ~int8 | ~int16 | ~int32 | ~int64 |
 		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
 		~float32 | ~float64 |
-		~string | time.Time
+		~bool | ~string | time.Time
 }
 
 // valuer is provides access to the field's value without needing to use
@@ -128,6 +135,13 @@ type Set interface {
 type fieldConfig struct {
 	name   string
 	legacy bool
+	detail bool
+
+	typ         reflect.Type
+	description string
+	unit        string
+	lookback    string
+	source      string
 }
 
 // ValidateSet tests whether a Set is valid.
@@ -163,8 +177,14 @@ func parseStructField(sf reflect.StructField) *fieldConfig {
 		return nil
 	}
 	f := &fieldConfig{
-		name:   strcase.ToSnake(sf.Name),
-		legacy: false,
+		name:        strcase.ToSnake(sf.Name),
+		legacy:      false,
+		detail:      sf.Type.Implements(detailerType),
+		typ:         valueType(sf.Type),
+		description: sf.Tag.Get(fieldTagDescription),
+		unit:        sf.Tag.Get(fieldTagUnit),
+		lookback:    sf.Tag.Get(fieldTagLookback),
+		source:      sf.Tag.Get(fieldTagSource),
 	}
 	if tag != "" {
 		parts := strings.Split(tag, fieldTagSeperator)
@@ -182,6 +202,14 @@ func parseStructField(sf reflect.StructField) *fieldConfig {
 	return f
 }
 
+// valueType returns the type of the value held by a Field or Detail of type t.
+func valueType(t reflect.Type) reflect.Type {
+	if sf, ok := t.FieldByName("value"); ok {
+		return sf.Type
+	}
+	return nil
+}
+
 // iterSetFields is an internal helper for looping across all the Fields in s.
 // It is also responsible for parsing the struct's tag.
 //
@@ -207,6 +235,8 @@ func iterSetFields(s Set, cb func(*fieldConfig, any) error) error {
 // SetFields returns a slice containing the names of the fields for s.
 //
 // If namespace is true the field names will be prefixed with the namespace.
+//
+// Detail fields are not included.
 func SetFields(s Set, namespace bool) []string {
 	var fs []string
 	prefix := ""
@@ -216,6 +246,9 @@ func SetFields(s Set, namespace bool) []string {
 		legacyPrefix = fmt.Sprintf("%s%c", NamespaceLegacy, nameSeparator)
 	}
 	_ = iterSetFields(s, func(f *fieldConfig, _ any) error {
+		if f.detail {
+			return nil
+		}
 		if f.legacy {
 			fs = append(fs, legacyPrefix+f.name)
 		} else {
@@ -230,9 +263,14 @@ func SetFields(s Set, namespace bool) []string {
 //
 // The values are either `nil` if the Field is not set, or the value that was
 // set.
+//
+// Detail fields are not included.
 func SetValues(s Set) []any {
 	var vs []any
-	_ = iterSetFields(s, func(_ *fieldConfig, v any) error {
+	_ = iterSetFields(s, func(f *fieldConfig, v any) error {
+		if f.detail {
+			return nil
+		}
 		vs = append(vs, v)
 		return nil
 	})
@@ -257,6 +295,8 @@ func SetAsMap(s Set, namespace bool) map[string]any {
 // SetAsMapWithNamespace returns a map where the outer map contains keys
 // corresponding to the namespace, and each inner map contains each field name
 // mapped to the value of the field.
+//
+// Unlike SetAsMap, Detail fields are included.
 func SetAsMapWithNamespace(s Set) map[string]map[string]any {
 	m := make(map[string]map[string]any)
 	_ = iterSetFields(s, func(f *fieldConfig, v any) error {
@@ -275,3 +315,61 @@ func SetAsMapWithNamespace(s Set) map[string]map[string]any {
 	})
 	return m
 }
+
+// Description contains the metadata describing a field in a Set.
+type Description struct {
+	// Name is the name of the field, prefixed with its namespace.
+	Name string
+
+	// Type is the Go type of the field's value.
+	Type reflect.Type
+
+	// Detail is true if the field is a Detail, and is only present in output
+	// that preserves the structure of each Set.
+	Detail bool
+
+	// Description explains what the field measures.
+	Description string
+
+	// Unit is the unit of the field's value, such as "count" or "hours".
+	Unit string
+
+	// Lookback is the period of time the field's value is calculated over.
+	Lookback string
+
+	// Source names the data source that produces the field's value.
+	Source string
+}
+
+// Describe returns the Description of each field in s.
+//
+// The metadata is read from the "desc", "unit", "lookback" and "source" tags
+// on each field. For example:
+//
+//	type FooSet struct {
+//		BarCount signal.Field[int] `desc:"Number of bars." unit:"count" lookback:"90 days" source:"Foo API"`
+//	}
+//
+// The fields are returned in the same order as SetFields, except Detail fields
+// are included.
+func Describe(s Set) []Description {
+	var ds []Description
+	_ = iterSetFields(s, func(f *fieldConfig, _ any) error {
+		ns := s.Namespace()
+		if f.legacy {
+			ns = NamespaceLegacy
+		}
+		d := Description{
+			Name:        fmt.Sprintf("%s%c%s", ns, nameSeparator, f.name),
+			Type:        f.typ,
+			Detail:      f.detail,
+			Description: f.description,
+			Unit:        f.unit,
+			Lookback:    f.lookback,
+			Source:      f.source,
+		}
+		ds = append(ds, d)
+		return nil
+	})
+	return ds
+}
@@ -0,0 +1,45 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signal
+
+import (
+	"reflect"
+	"testing"
+)
+
+type describedSet struct { //nolint:govet
+	OpenCount Field[int]     `desc:"Number of open things." unit:"count" source:"Test API"`
+	Frequency Field[float64] `signal:"legacy" desc:"Things per week." unit:"things/week" lookback:"1 year"`
+	Weekly    Detail[[]int]  `desc:"Things in each week."`
+	Plain     Field[string]
+	Ignored   Field[int] `signal:"-"`
+	NotField  string
+}
+
+func (s *describedSet) Namespace() Namespace {
+	return "described"
+}
+
+func TestDescribe(t *testing.T) {
+	want := []Description{
+		{Name: "described.open_count", Type: reflect.TypeOf(0), Description: "Number of open things.", Unit: "count", Source: "Test API"},
+		{Name: "legacy.frequency", Type: reflect.TypeOf(0.0), Description: "Things per week.", Unit: "things/week", Lookback: "1 year"},
+		{Name: "described.weekly", Type: reflect.TypeOf([]int{}), Detail: true, Description: "Things in each week."},
+		{Name: "described.plain", Type: reflect.TypeOf("")},
+	}
+	if got := Describe(&describedSet{}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Describe() = %v, want %v", got, want)
+	}
+}
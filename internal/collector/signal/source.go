@@ -38,3 +38,37 @@ type Source interface {
 	// or if the context is cancelled.
 	Get(ctx context.Context, r projectrepo.Repo, jobID string) (Set, error)
 }
+
+// Dependent is implemented by a Source that must be collected after the
+// Sources for other namespaces, for example because it derives its signals
+// from theirs.
+//
+// The Sets collected for the namespaces are available to Get through
+// Dependency.
+type Dependent interface {
+	// DependsOn returns the namespaces of the Sets the Source depends on.
+	//
+	// Namespaces that are not collected for a repository are ignored.
+	DependsOn() []Namespace
+}
+
+// dependenciesKey is the context key for the Sets a Source depends on.
+type dependenciesKey struct{}
+
+// WithDependencies returns a copy of ctx holding the Sets collected for the
+// namespaces a Dependent Source depends on.
+func WithDependencies(ctx context.Context, sets []Set) context.Context {
+	return context.WithValue(ctx, dependenciesKey{}, sets)
+}
+
+// Dependency returns the Set collected for the namespace ns, if it is one of
+// the namespaces the Source for ctx depends on.
+func Dependency(ctx context.Context, ns Namespace) (Set, bool) {
+	sets, _ := ctx.Value(dependenciesKey{}).([]Set)
+	for _, s := range sets {
+		if s.Namespace() == ns {
+			return s, true
+		}
+	}
+	return nil, false
+}
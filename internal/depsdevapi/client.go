@@ -0,0 +1,198 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package depsdevapi provides a minimal client for the deps.dev HTTP API.
+package depsdevapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the base URL of the public deps.dev API.
+//
+// The alpha version of the API is used as it is the only version that
+// supports querying dependents.
+const DefaultBaseURL = "https://api.deps.dev/v3alpha"
+
+// ErrNotFound is returned when the requested resource does not exist.
+//
+// It should be used with errors.Is.
+var ErrNotFound = errors.New("not found")
+
+// Client provides simple access to the deps.dev API.
+type Client struct {
+	http    *http.Client
+	baseURL string
+}
+
+// NewClient creates a new instance of Client that sends requests to the API
+// at baseURL using client.
+func NewClient(client *http.Client, baseURL string) *Client {
+	return &Client{
+		http:    client,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// VersionKey identifies a single version of a package.
+type VersionKey struct {
+	System  string `json:"system"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// PackageKey identifies a package.
+type PackageKey struct {
+	System string `json:"system"`
+	Name   string `json:"name"`
+}
+
+// Package contains the versions available for a package.
+type Package struct {
+	PackageKey PackageKey `json:"packageKey"`
+	Versions   []struct {
+		VersionKey  VersionKey `json:"versionKey"`
+		PublishedAt time.Time  `json:"publishedAt"`
+		IsDefault   bool       `json:"isDefault"`
+	} `json:"versions"`
+}
+
+// DefaultVersion returns the key of the default version of the package, which
+// is usually the latest release.
+//
+// If the package has no default version ok will be false.
+func (p *Package) DefaultVersion() (key VersionKey, ok bool) {
+	for _, v := range p.Versions {
+		if v.IsDefault {
+			return v.VersionKey, true
+		}
+	}
+	return VersionKey{}, false
+}
+
+// PublishedAt returns the time the given version of the package was
+// published.
+//
+// If the version is not known, or has no publish time, ok will be false.
+func (p *Package) PublishedAt(version string) (t time.Time, ok bool) {
+	for _, v := range p.Versions {
+		if v.VersionKey.Version == version && !v.PublishedAt.IsZero() {
+			return v.PublishedAt, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Dependents contains the number of packages that depend on a package
+// version.
+type Dependents struct {
+	DependentCount         int `json:"dependentCount"`
+	DirectDependentCount   int `json:"directDependentCount"`
+	IndirectDependentCount int `json:"indirectDependentCount"`
+}
+
+// ProjectID returns the deps.dev project ID for the repository at u, such as
+// "github.com/ossf/criticality_score".
+//
+// If the repository is not hosted somewhere that deps.dev supports ok will be
+// false.
+func ProjectID(u *url.URL) (id string, ok bool) {
+	switch hn := strings.ToLower(u.Hostname()); hn {
+	case "github.com", "gitlab.com", "bitbucket.org":
+		return hn + "/" + strings.ToLower(strings.Trim(u.Path, "/")), true
+	default:
+		return "", false
+	}
+}
+
+// ProjectPackageVersions returns the keys of the package versions that are
+// built from the project with the given id, such as
+// "github.com/ossf/criticality_score".
+func (c *Client) ProjectPackageVersions(ctx context.Context, projectID string) ([]VersionKey, error) {
+	var resp struct {
+		Versions []struct {
+			VersionKey VersionKey `json:"versionKey"`
+		} `json:"versions"`
+	}
+	if err := c.get(ctx, "projects/"+url.PathEscape(projectID)+":packageversions", &resp); err != nil {
+		return nil, err
+	}
+	keys := make([]VersionKey, 0, len(resp.Versions))
+	for _, v := range resp.Versions {
+		keys = append(keys, v.VersionKey)
+	}
+	return keys, nil
+}
+
+// Package returns details about the package identified by key.
+func (c *Client) Package(ctx context.Context, key PackageKey) (*Package, error) {
+	p := &Package{}
+	if err := c.get(ctx, packagePath(key.System, key.Name), p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Dependents returns the number of packages that depend on the package
+// version identified by key.
+func (c *Client) Dependents(ctx context.Context, key VersionKey) (*Dependents, error) {
+	d := &Dependents{}
+	if err := c.get(ctx, versionPath(key)+":dependents", d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// get requests the API endpoint at path and parses the JSON response into v.
+//
+// The path must already be escaped.
+func (c *Client) get(ctx context.Context, path string, v any) error {
+	u := c.baseURL + "/" + path
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return fmt.Errorf("new request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("deps.dev request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("%w: %s", ErrNotFound, u)
+	case resp.StatusCode < 200 || 300 <= resp.StatusCode:
+		return fmt.Errorf("deps.dev request %s: unexpected status %s", u, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("reading response for %s: %w", u, err)
+	}
+	return nil
+}
+
+func packagePath(system, name string) string {
+	return "systems/" + url.PathEscape(strings.ToLower(system)) + "/packages/" + url.PathEscape(name)
+}
+
+func versionPath(key VersionKey) string {
+	return packagePath(key.System, key.Name) + "/versions/" + url.PathEscape(key.Version)
+}
@@ -0,0 +1,35 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package depsdevapi
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ossf/criticality_score/v2/internal/retry"
+)
+
+// NewTransport returns an http.RoundTripper for communicating with the
+// deps.dev API.
+//
+// Requests are retried if they are rate limited or fail with a server error.
+func NewTransport(inner http.RoundTripper) http.RoundTripper {
+	return retry.NewRoundTripper(inner,
+		retry.InitialDelay(time.Minute),
+		retry.RetryAfter(retry.RetryAfterSeconds),
+		retry.Strategy(retry.TooManyRequests),
+		retry.Strategy(retry.ServerError),
+	)
+}
@@ -15,6 +15,7 @@
 package retry
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"time"
@@ -68,8 +69,24 @@ type BackoffFn func(time.Duration) time.Duration
 
 // SleepFn must cause the current goroutine to sleep for the allocated Duration.
 //
-// The usual implementation of this is time.Sleep. This is provided for testing.
-type sleepFn func(time.Duration)
+// If ctx is done before the Duration has passed, SleepFn must return early with
+// the error from ctx.
+//
+// The usual implementation of this is sleepContext. This is provided for
+// testing.
+type sleepFn func(context.Context, time.Duration) error
+
+// sleepContext sleeps for the Duration d, or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
 
 // DefaultBackoff will double the duration d if it is greater than zero,
 // otherwise it returns 1 minute.
@@ -132,7 +149,7 @@ func MakeOptions(os ...Option) *Options {
 	opts := &Options{
 		maxRetries:   DefaultMaxRetries,
 		initialDelay: DefaultInitialDuration,
-		sleep:        time.Sleep,
+		sleep:        sleepContext,
 		backoff:      DefaultBackoff,
 	}
 	for _, o := range os {
@@ -168,6 +185,7 @@ func NewRequest(r *http.Request, client func(*http.Request) (*http.Response, err
 //  3. The number of attempts exceeds MaxRetries
 //  4. No RetryStrategy was returned or only NoRetry, and RetryAfter() had no
 //     delay.
+//  5. The request's context is done while waiting to retry.
 //
 // If Done returns true, Do must never be called again, otherwise Do will
 // return ErrorNoMoreAttempts.
@@ -197,8 +215,10 @@ func (r *Request) Do() (*http.Response, error) {
 	if r.attempts > 0 {
 		// This is a retry!
 		if r.delay > 0 {
-			// Wait if we have a delay
-			r.o.sleep(r.delay)
+			// Wait if we have a delay, giving up if the request is canceled
+			if err := r.o.sleep(r.r.Context(), r.delay); err != nil {
+				return r.onError(err)
+			}
 		}
 		// Update the delay
 		r.delay = r.o.backoff(r.delay)
@@ -15,6 +15,7 @@
 package retry
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -118,8 +119,9 @@ func TestRetryAfterDuration(t *testing.T) {
 	opts := MakeOptions(RetryAfter(func(_ *http.Response) time.Duration {
 		return time.Minute
 	}))
-	opts.sleep = func(d time.Duration) {
+	opts.sleep = func(_ context.Context, d time.Duration) error {
 		slept += d
+		return nil
 	}
 	req := NewRequest(&http.Request{}, func(r *http.Request) (*http.Response, error) {
 		return &http.Response{
@@ -141,6 +143,38 @@ func TestRetryAfterDuration(t *testing.T) {
 	}
 }
 
+func TestRetryAfterContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://example.com/", nil)
+	if err != nil {
+		t.Fatalf("NewRequestWithContext() = %v", err)
+	}
+	req := NewRequest(r, func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusBadRequest,
+			Body:       io.NopCloser(strings.NewReader("")),
+		}, nil
+	}, MakeOptions(RetryAfter(func(_ *http.Response) time.Duration {
+		return time.Hour
+	})))
+	req.Do()
+	if req.Done() {
+		t.Fatalf("Done() == true; want false")
+	}
+
+	cancel()
+	resp, err := req.Do()
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Do() returned err %v; want %v", err, context.Canceled)
+	}
+	if resp != nil {
+		t.Fatalf("Do() returned response %v; want nil", resp)
+	}
+	if !req.Done() {
+		t.Fatalf("Done() == false; want true")
+	}
+}
+
 func TestZeroMaxRetriesOnlyTriesOnce(t *testing.T) {
 	req := NewRequest(&http.Request{}, func(r *http.Request) (*http.Response, error) {
 		return &http.Response{
@@ -0,0 +1,49 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retry
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryAfterSeconds implements RetryAfterFn for APIs that set the Retry-After
+// header to the number of seconds to wait before retrying.
+func RetryAfterSeconds(r *http.Response) time.Duration {
+	if v := r.Header.Get("Retry-After"); v != "" {
+		retryAfterSeconds, _ := strconv.ParseInt(v, 10, 64) // Error handling is noop.
+		return time.Duration(retryAfterSeconds) * time.Second
+	}
+	return 0
+}
+
+// TooManyRequests implements RetryStrategyFn. Requests that are rate limited
+// with a 429 status are retried after the initial delay.
+func TooManyRequests(r *http.Response) (RetryStrategy, error) {
+	if r.StatusCode != http.StatusTooManyRequests {
+		return NoRetry, nil
+	}
+	return RetryWithInitialDelay, nil
+}
+
+// ServerError implements RetryStrategyFn. Requests that fail with a 5xx status
+// are retried immediately.
+func ServerError(r *http.Response) (RetryStrategy, error) {
+	if r.StatusCode < 500 || 600 <= r.StatusCode {
+		return NoRetry, nil
+	}
+	return RetryImmediate, nil
+}
@@ -0,0 +1,89 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retry
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryAfterSeconds(t *testing.T) {
+	tests := []struct { //nolint:govet
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{name: "no header", want: 0},
+		{name: "seconds", value: "30", want: 30 * time.Second},
+		{name: "invalid", value: "junk", want: 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := &http.Response{Header: http.Header{}}
+			if test.value != "" {
+				r.Header.Set("Retry-After", test.value)
+			}
+			if got := RetryAfterSeconds(r); got != test.want {
+				t.Fatalf("RetryAfterSeconds() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestTooManyRequests(t *testing.T) {
+	tests := []struct {
+		status int
+		want   RetryStrategy
+	}{
+		{status: http.StatusTooManyRequests, want: RetryWithInitialDelay},
+		{status: http.StatusForbidden, want: NoRetry},
+		{status: http.StatusInternalServerError, want: NoRetry},
+	}
+	for _, test := range tests {
+		t.Run(http.StatusText(test.status), func(t *testing.T) {
+			got, err := TooManyRequests(&http.Response{StatusCode: test.status})
+			if err != nil {
+				t.Fatalf("TooManyRequests() returned err %v; want no err", err)
+			}
+			if got != test.want {
+				t.Fatalf("TooManyRequests() = %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestServerError(t *testing.T) {
+	tests := []struct {
+		status int
+		want   RetryStrategy
+	}{
+		{status: http.StatusInternalServerError, want: RetryImmediate},
+		{status: http.StatusBadGateway, want: RetryImmediate},
+		{status: http.StatusNotFound, want: NoRetry},
+		{status: http.StatusTooManyRequests, want: NoRetry},
+	}
+	for _, test := range tests {
+		t.Run(http.StatusText(test.status), func(t *testing.T) {
+			got, err := ServerError(&http.Response{StatusCode: test.status})
+			if err != nil {
+				t.Fatalf("ServerError() returned err %v; want no err", err)
+			}
+			if got != test.want {
+				t.Fatalf("ServerError() = %s, want %s", got, test.want)
+			}
+		})
+	}
+}
@@ -80,6 +80,8 @@ func (s *Scorer) Score(signals []signal.Set) float64 {
 				record[k] = float64(r)
 			case byte:
 				record[k] = float64(r)
+			case bool:
+				record[k] = boolToFloat(r)
 			}
 		}
 	}
@@ -90,16 +92,24 @@ func (s *Scorer) ScoreRaw(raw map[string]string) float64 {
 	record := make(map[string]float64)
 	for k, rawV := range raw {
 		// TODO: improve this behavior
-		v, err := strconv.ParseFloat(rawV, 64)
-		if err != nil {
-			// Failed to parse raw into a float, ignore the field
-			continue
+		if v, err := strconv.ParseFloat(rawV, 64); err == nil {
+			record[k] = v
+		} else if b, err := strconv.ParseBool(rawV); err == nil {
+			record[k] = boolToFloat(b)
 		}
-		record[k] = v
+		// Otherwise the raw value could not be parsed, so ignore the field.
 	}
 	return s.a.Score(record)
 }
 
+// boolToFloat converts b to 1 if it is true, and 0 otherwise.
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
 func (s *Scorer) Name() string {
 	return s.name
 }
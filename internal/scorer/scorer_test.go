@@ -22,7 +22,8 @@ import (
 )
 
 type testAlgo struct {
-	UpdatedCount signal.Field[int] `signal:"legacy"`
+	UpdatedCount signal.Field[int]  `signal:"legacy"`
+	IsArchived   signal.Field[bool] `signal:"legacy"`
 }
 
 func (t testAlgo) Score(record map[string]float64) float64 {
@@ -56,6 +57,19 @@ func TestScorer_ScoreRaw(t *testing.T) {
 			},
 			want: 3,
 		},
+		{
+			name: "bool test",
+			s: &Scorer{
+				name: "Valid",
+				a:    testAlgo{},
+			},
+			raw: map[string]string{
+				"one":   "1",
+				"true":  "true",
+				"false": "false",
+			},
+			want: 2,
+		},
 		{
 			name: "invalid",
 			s: &Scorer{
@@ -166,9 +180,10 @@ func TestScorer_Score(t *testing.T) {
 		signals: []signal.Set{
 			&testAlgo{
 				UpdatedCount: signal.Val(1),
+				IsArchived:   signal.Val(true),
 			},
 		},
-		want: 1,
+		want: 2,
 	}
 
 	s := &Scorer{
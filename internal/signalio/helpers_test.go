@@ -28,6 +28,21 @@ func (t testSet) Namespace() signal.Namespace {
 	return "test"
 }
 
+type testDetailSet struct {
+	UpdatedCount signal.Field[int]
+	Weekly       signal.Detail[[]int]
+}
+
+func (t testDetailSet) Namespace() signal.Namespace {
+	return "detail"
+}
+
+func weekly(v []int) signal.Detail[[]int] {
+	var d signal.Detail[[]int]
+	d.Set(v)
+	return d
+}
+
 func Test_marshalToMap(t *testing.T) {
 	tests := []struct { //nolint:govet
 		name    string
@@ -83,6 +98,16 @@ func Test_marshalToMap(t *testing.T) {
 			},
 			want: map[string]string{"test.updated_count": "1"},
 		},
+		{
+			name: "detail skipped",
+			signals: []signal.Set{
+				&testDetailSet{
+					UpdatedCount: signal.Val(1),
+					Weekly:       weekly([]int{1, 2, 3}),
+				},
+			},
+			want: map[string]string{"detail.updated_count": "1"},
+		},
 		{
 			name: "empty signals",
 			extra: []Field{
@@ -152,6 +177,13 @@ func Test_fieldsFromSignalSets(t *testing.T) {
 			},
 			want: []string{"test.updated_count"},
 		},
+		{
+			name: "detail skipped",
+			sets: []signal.Set{
+				&testDetailSet{},
+			},
+			want: []string{"detail.updated_count"},
+		},
 		{
 			name: "empty sets",
 			extra: []string{
@@ -0,0 +1,305 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signalio
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/ossf/criticality_score/v2/internal/collector/signal"
+)
+
+// SchemaType is the type of schema that can be generated for the records
+// produced by a Writer.
+type SchemaType int
+
+const (
+	// SchemaTypeJSONSchema is a JSON Schema for the records written by
+	// JSONWriter.
+	SchemaTypeJSONSchema = SchemaType(iota)
+
+	// SchemaTypeBigQueryJSON is a BigQuery table schema for loading the
+	// records written by JSONWriter.
+	SchemaTypeBigQueryJSON
+
+	// SchemaTypeBigQueryCSV is a BigQuery table schema for loading the
+	// records written by CSVWriter.
+	SchemaTypeBigQueryCSV
+)
+
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+var (
+	ErrorUnknownSchemaType = errors.New("unknown schema type")
+	ErrorUnsupportedType   = errors.New("unsupported type")
+
+	timeType = reflect.TypeOf(time.Time{})
+)
+
+// String implements the fmt.Stringer interface.
+func (t SchemaType) String() string {
+	text, err := t.MarshalText()
+	if err != nil {
+		return ""
+	}
+	return string(text)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (t SchemaType) MarshalText() ([]byte, error) {
+	switch t {
+	case SchemaTypeJSONSchema:
+		return []byte("json-schema"), nil
+	case SchemaTypeBigQueryJSON:
+		return []byte("bigquery-json"), nil
+	case SchemaTypeBigQueryCSV:
+		return []byte("bigquery-csv"), nil
+	default:
+		return []byte{}, ErrorUnknownSchemaType
+	}
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (t *SchemaType) UnmarshalText(text []byte) error {
+	switch {
+	case bytes.Equal(text, []byte("json-schema")):
+		*t = SchemaTypeJSONSchema
+	case bytes.Equal(text, []byte("bigquery-json")):
+		*t = SchemaTypeBigQueryJSON
+	case bytes.Equal(text, []byte("bigquery-csv")):
+		*t = SchemaTypeBigQueryCSV
+	default:
+		return ErrorUnknownSchemaType
+	}
+	return nil
+}
+
+// Write writes the schema for records containing the signals in emptySets and
+// the extra fields to w as indented JSON.
+//
+// The type of each extra field is taken from its Value, which is usually the
+// zero value of the type. Extra fields with a nil Value are treated as
+// strings.
+func (t SchemaType) Write(w io.Writer, emptySets []signal.Set, extra ...Field) error {
+	var schema any
+	var err error
+	switch t {
+	case SchemaTypeJSONSchema:
+		schema, err = JSONSchema(emptySets, extra...)
+	case SchemaTypeBigQueryJSON:
+		schema, err = BigQueryJSONSchema(emptySets, extra...)
+	case SchemaTypeBigQueryCSV:
+		schema, err = BigQueryCSVSchema(emptySets, extra...)
+	default:
+		return ErrorUnknownSchemaType
+	}
+	if err != nil {
+		return err
+	}
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return e.Encode(schema)
+}
+
+// JSONSchema returns a JSON Schema describing the records written by
+// JSONWriter for the signals in emptySets and the extra fields.
+//
+// Each namespace is an object property of the record. Signals that are not set
+// are written as null, so every signal also allows null.
+func JSONSchema(emptySets []signal.Set, extra ...Field) (map[string]any, error) {
+	props := make(map[string]any)
+	namespaces := make(map[string]map[string]any)
+	for _, s := range emptySets {
+		for _, d := range signal.Describe(s) {
+			ns, name, _ := strings.Cut(d.Name, ".")
+			p, err := jsonSchemaType(d.Type)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", d.Name, err)
+			}
+			p["type"] = []any{p["type"], "null"}
+			if d.Description != "" {
+				p["description"] = d.Description
+			}
+			nsProps, ok := namespaces[ns]
+			if !ok {
+				nsProps = make(map[string]any)
+				namespaces[ns] = nsProps
+				props[ns] = map[string]any{
+					"type":       "object",
+					"properties": nsProps,
+				}
+			}
+			nsProps[name] = p
+		}
+	}
+	for _, f := range extra {
+		p, err := jsonSchemaType(extraType(f))
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", f.Key, err)
+		}
+		props[f.Key] = p
+	}
+	return map[string]any{
+		"$schema":    jsonSchemaDialect,
+		"type":       "object",
+		"properties": props,
+	}, nil
+}
+
+// jsonSchemaType returns the JSON Schema for a value of type t, as encoded by
+// encoding/json.
+func jsonSchemaType(t reflect.Type) (map[string]any, error) {
+	if t == timeType {
+		return map[string]any{"type": "string", "format": "date-time"}, nil
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return map[string]any{"type": "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}, nil
+	case reflect.String:
+		return map[string]any{"type": "string"}, nil
+	case reflect.Slice, reflect.Array:
+		items, err := jsonSchemaType(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "array", "items": items}, nil
+	case reflect.Map:
+		values, err := jsonSchemaType(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "object", "additionalProperties": values}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrorUnsupportedType, t)
+	}
+}
+
+// BigQueryField is a column in a BigQuery table schema, as used by the bq
+// command line tool.
+type BigQueryField struct {
+	Name        string          `json:"name"`
+	Type        string          `json:"type"`
+	Mode        string          `json:"mode,omitempty"`
+	Description string          `json:"description,omitempty"`
+	Fields      []BigQueryField `json:"fields,omitempty"`
+}
+
+// BigQueryJSONSchema returns a BigQuery table schema for loading the records
+// written by JSONWriter for the signals in emptySets and the extra fields.
+//
+// Each namespace is a RECORD column containing its signals.
+func BigQueryJSONSchema(emptySets []signal.Set, extra ...Field) ([]BigQueryField, error) {
+	var fields []BigQueryField
+	index := make(map[string]int)
+	for _, s := range emptySets {
+		for _, d := range signal.Describe(s) {
+			ns, name, _ := strings.Cut(d.Name, ".")
+			f, err := bigQueryField(name, d.Type)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", d.Name, err)
+			}
+			f.Description = d.Description
+			i, ok := index[ns]
+			if !ok {
+				i = len(fields)
+				index[ns] = i
+				fields = append(fields, BigQueryField{Name: ns, Type: "RECORD", Mode: "NULLABLE"})
+			}
+			fields[i].Fields = append(fields[i].Fields, f)
+		}
+	}
+	return appendBigQueryExtras(fields, extra)
+}
+
+// BigQueryCSVSchema returns a BigQuery table schema for loading the records
+// written by CSVWriter for the signals in emptySets and the extra fields.
+//
+// The columns are in the same order as the CSV header. As BigQuery column
+// names can not contain ".", the namespace is separated from the signal name
+// with "_". Detail fields are not written to CSV, so they are not included.
+func BigQueryCSVSchema(emptySets []signal.Set, extra ...Field) ([]BigQueryField, error) {
+	var fields []BigQueryField
+	for _, s := range emptySets {
+		for _, d := range signal.Describe(s) {
+			if d.Detail {
+				continue
+			}
+			f, err := bigQueryField(strings.ReplaceAll(d.Name, ".", "_"), d.Type)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", d.Name, err)
+			}
+			f.Description = d.Description
+			fields = append(fields, f)
+		}
+	}
+	return appendBigQueryExtras(fields, extra)
+}
+
+func appendBigQueryExtras(fields []BigQueryField, extra []Field) ([]BigQueryField, error) {
+	for _, e := range extra {
+		f, err := bigQueryField(e.Key, extraType(e))
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", e.Key, err)
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// bigQueryField returns a BigQuery column for a value of type t.
+func bigQueryField(name string, t reflect.Type) (BigQueryField, error) {
+	f := BigQueryField{Name: name, Mode: "NULLABLE"}
+	if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		f.Mode = "REPEATED"
+		t = t.Elem()
+	}
+	switch {
+	case t == timeType:
+		f.Type = "TIMESTAMP"
+	case t.Kind() == reflect.Bool:
+		f.Type = "BOOLEAN"
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uintptr:
+		f.Type = "INTEGER"
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		f.Type = "FLOAT"
+	case t.Kind() == reflect.String:
+		f.Type = "STRING"
+	case t.Kind() == reflect.Map:
+		// BigQuery does not have a map type, so keep the JSON object.
+		f.Type = "JSON"
+	default:
+		return BigQueryField{}, fmt.Errorf("%w: %s", ErrorUnsupportedType, t)
+	}
+	return f, nil
+}
+
+// extraType returns the type of the value held by the extra field.
+func extraType(f Field) reflect.Type {
+	if f.Value == nil {
+		return reflect.TypeOf("")
+	}
+	return reflect.TypeOf(f.Value)
+}
@@ -0,0 +1,169 @@
+// Copyright 2023 Criticality Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signalio_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ossf/criticality_score/v2/internal/collector/signal"
+	"github.com/ossf/criticality_score/v2/internal/signalio"
+)
+
+type schemaSet struct {
+	Count    signal.Field[int]       `desc:"Number of things."`
+	Updated  signal.Field[time.Time] `signal:"legacy"`
+	Weekly   signal.Detail[[]int]    `desc:"Things in each week."`
+	ByOwner  signal.Detail[map[string]int]
+	HasThing signal.Field[bool]
+}
+
+func (s *schemaSet) Namespace() signal.Namespace {
+	return "schema"
+}
+
+var schemaExtras = []signalio.Field{
+	{Key: "default_score", Value: ""},
+	{Key: "collection_date", Value: time.Time{}},
+}
+
+func TestSchemaTypeUnmarshalText(t *testing.T) {
+	for _, want := range []signalio.SchemaType{
+		signalio.SchemaTypeJSONSchema,
+		signalio.SchemaTypeBigQueryJSON,
+		signalio.SchemaTypeBigQueryCSV,
+	} {
+		t.Run(want.String(), func(t *testing.T) {
+			var got signalio.SchemaType
+			if err := got.UnmarshalText([]byte(want.String())); err != nil {
+				t.Fatalf("UnmarshalText() = %v, want no error", err)
+			}
+			if got != want {
+				t.Fatalf("UnmarshalText() parsed %v, want %v", got, want)
+			}
+		})
+	}
+	var st signalio.SchemaType
+	if err := st.UnmarshalText([]byte("xml")); !errors.Is(err, signalio.ErrorUnknownSchemaType) {
+		t.Fatalf("UnmarshalText() = %v, want %v", err, signalio.ErrorUnknownSchemaType)
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	got, err := signalio.JSONSchema([]signal.Set{&schemaSet{}}, schemaExtras...)
+	if err != nil {
+		t.Fatalf("JSONSchema() = %v, want no error", err)
+	}
+	want := map[string]any{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type":    "object",
+		"properties": map[string]any{
+			"schema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"count":     map[string]any{"type": []any{"integer", "null"}, "description": "Number of things."},
+					"weekly":    map[string]any{"type": []any{"array", "null"}, "items": map[string]any{"type": "integer"}, "description": "Things in each week."},
+					"by_owner":  map[string]any{"type": []any{"object", "null"}, "additionalProperties": map[string]any{"type": "integer"}},
+					"has_thing": map[string]any{"type": []any{"boolean", "null"}},
+				},
+			},
+			"legacy": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"updated": map[string]any{"type": []any{"string", "null"}, "format": "date-time"},
+				},
+			},
+			"default_score":   map[string]any{"type": "string"},
+			"collection_date": map[string]any{"type": "string", "format": "date-time"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("JSONSchema() = %v, want %v", got, want)
+	}
+}
+
+func TestBigQueryJSONSchema(t *testing.T) {
+	got, err := signalio.BigQueryJSONSchema([]signal.Set{&schemaSet{}}, schemaExtras...)
+	if err != nil {
+		t.Fatalf("BigQueryJSONSchema() = %v, want no error", err)
+	}
+	want := []signalio.BigQueryField{
+		{Name: "schema", Type: "RECORD", Mode: "NULLABLE", Fields: []signalio.BigQueryField{
+			{Name: "count", Type: "INTEGER", Mode: "NULLABLE", Description: "Number of things."},
+			{Name: "weekly", Type: "INTEGER", Mode: "REPEATED", Description: "Things in each week."},
+			{Name: "by_owner", Type: "JSON", Mode: "NULLABLE"},
+			{Name: "has_thing", Type: "BOOLEAN", Mode: "NULLABLE"},
+		}},
+		{Name: "legacy", Type: "RECORD", Mode: "NULLABLE", Fields: []signalio.BigQueryField{
+			{Name: "updated", Type: "TIMESTAMP", Mode: "NULLABLE"},
+		}},
+		{Name: "default_score", Type: "STRING", Mode: "NULLABLE"},
+		{Name: "collection_date", Type: "TIMESTAMP", Mode: "NULLABLE"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("BigQueryJSONSchema() = %v, want %v", got, want)
+	}
+}
+
+func TestBigQueryCSVSchema(t *testing.T) {
+	sets := []signal.Set{&schemaSet{}}
+	got, err := signalio.BigQueryCSVSchema(sets, schemaExtras...)
+	if err != nil {
+		t.Fatalf("BigQueryCSVSchema() = %v, want no error", err)
+	}
+	want := []signalio.BigQueryField{
+		{Name: "schema_count", Type: "INTEGER", Mode: "NULLABLE", Description: "Number of things."},
+		{Name: "legacy_updated", Type: "TIMESTAMP", Mode: "NULLABLE"},
+		{Name: "schema_has_thing", Type: "BOOLEAN", Mode: "NULLABLE"},
+		{Name: "default_score", Type: "STRING", Mode: "NULLABLE"},
+		{Name: "collection_date", Type: "TIMESTAMP", Mode: "NULLABLE"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("BigQueryCSVSchema() = %v, want %v", got, want)
+	}
+
+	// The columns must line up with the header written by CSVWriter.
+	var buf bytes.Buffer
+	w := signalio.CSVWriter(&buf, sets, "default_score", "collection_date")
+	if err := w.WriteSignals([]signal.Set{&schemaSet{}}); err != nil {
+		t.Fatalf("WriteSignals() = %v, want no error", err)
+	}
+	header, _, _ := bytes.Cut(buf.Bytes(), []byte("\n"))
+	if n := len(bytes.Split(header, []byte(","))); n != len(got) {
+		t.Fatalf("CSV header has %d columns, schema has %d", n, len(got))
+	}
+}
+
+func TestSchemaTypeWrite(t *testing.T) {
+	var buf bytes.Buffer
+	if err := signalio.SchemaTypeBigQueryJSON.Write(&buf, []signal.Set{&schemaSet{}}); err != nil {
+		t.Fatalf("Write() = %v, want no error", err)
+	}
+	var fields []signalio.BigQueryField
+	if err := json.Unmarshal(buf.Bytes(), &fields); err != nil {
+		t.Fatalf("Unmarshal() = %v, want no error", err)
+	}
+	if len(fields) != 2 {
+		t.Fatalf("Write() wrote %d fields, want 2", len(fields))
+	}
+
+	if err := signalio.SchemaType(10).Write(&buf, nil); !errors.Is(err, signalio.ErrorUnknownSchemaType) {
+		t.Fatalf("Write() = %v, want %v", err, signalio.ErrorUnknownSchemaType)
+	}
+}